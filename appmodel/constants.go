@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package appmodel
+
+// Maximum string lengths, including the terminating null character.
+//
+// https://docs.microsoft.com/en-us/windows/win32/appxpkg/identity-constants
+const (
+	packageFullNameMaxLength        = 127 + 1 // PACKAGE_FULL_NAME_MAX_LENGTH
+	packageFamilyNameMaxLength      = 64 + 1  // PACKAGE_FAMILY_NAME_MAX_LENGTH
+	applicationUserModelIDMaxLength = 130 + 1 // APPLICATION_USER_MODEL_ID_MAX_LENGTH
+)
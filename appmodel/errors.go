@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package appmodel
+
+import "syscall"
+
+var (
+	// ErrNoPackage is returned when a process does not have a package
+	// identity.
+	ErrNoPackage = syscall.Errno(15700) // APPMODEL_ERROR_NO_PACKAGE
+
+	// ErrNoApplication is returned when a packaged process does not have
+	// an application identity.
+	ErrNoApplication = syscall.Errno(15703) // APPMODEL_ERROR_NO_APPLICATION
+)
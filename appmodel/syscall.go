@@ -0,0 +1,89 @@
+//go:build windows
+// +build windows
+
+package appmodel
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procGetPackageFullName        = modkernel32.NewProc("GetPackageFullName")
+	procGetPackageFamilyName      = modkernel32.NewProc("GetPackageFamilyName")
+	procGetApplicationUserModelID = modkernel32.NewProc("GetApplicationUserModelId")
+)
+
+// PackageFullName returns the full name of the package that a process
+// belongs to. It calls the GetPackageFullName windows API function.
+//
+// It returns ErrNoPackage if the process does not have a package identity.
+//
+// This call is only supported on Windows 8 or newer.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/appmodel/nf-appmodel-getpackagefullname
+func PackageFullName(process syscall.Handle) (string, error) {
+	return processString(procGetPackageFullName, process, packageFullNameMaxLength)
+}
+
+// PackageFamilyName returns the family name of the package that a process
+// belongs to. It calls the GetPackageFamilyName windows API function.
+//
+// It returns ErrNoPackage if the process does not have a package identity.
+//
+// This call is only supported on Windows 8 or newer.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/appmodel/nf-appmodel-getpackagefamilyname
+func PackageFamilyName(process syscall.Handle) (string, error) {
+	return processString(procGetPackageFamilyName, process, packageFamilyNameMaxLength)
+}
+
+// ApplicationUserModelID returns the application user model ID of a
+// process. It calls the GetApplicationUserModelId windows API function.
+//
+// It returns ErrNoPackage if the process does not have a package identity
+// and ErrNoApplication if the process is packaged but is not an
+// application.
+//
+// This call is only supported on Windows 8 or newer.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/appmodel/nf-appmodel-getapplicationusermodelid
+func ApplicationUserModelID(process syscall.Handle) (string, error) {
+	return processString(procGetApplicationUserModelID, process, applicationUserModelIDMaxLength)
+}
+
+// processString calls an app model function that writes a string about a
+// process into a caller supplied buffer.
+//
+// These functions return an error code directly instead of setting the
+// last error.
+func processString(proc *windows.LazyProc, process syscall.Handle, size uint32) (string, error) {
+	if err := proc.Find(); err != nil {
+		return "", err
+	}
+
+	for i := 0; i < 2; i++ {
+		length := size
+		buffer := make([]uint16, length)
+		r0, _, _ := syscall.Syscall(
+			proc.Addr(),
+			3,
+			uintptr(process),
+			uintptr(unsafe.Pointer(&length)),
+			uintptr(unsafe.Pointer(&buffer[0])))
+		switch syscall.Errno(r0) {
+		case 0:
+			return syscall.UTF16ToString(buffer), nil
+		case syscall.ERROR_INSUFFICIENT_BUFFER:
+			size = length
+		default:
+			return "", syscall.Errno(r0)
+		}
+	}
+
+	return "", syscall.ERROR_INSUFFICIENT_BUFFER
+}
@@ -0,0 +1,378 @@
+package authenticode_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc/authenticode"
+)
+
+var (
+	oidSignedData             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidCounterSignature       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 6}
+	oidSpcIndirectDataContent = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 4}
+	oidSpcPEImageData         = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 15}
+	oidSHA256                 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidECPublicKey            = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+)
+
+// contentInfo is encoded with an explicitly tagged content, which must be
+// constructed by explicit.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+// explicit wraps encoded content in an explicit [0] tag.
+func explicit(content []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: content}
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type signerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerialNumber
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+	UnauthenticatedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+type spcIndirectDataContent struct {
+	Data          spcAttributeTypeAndOptionalValue
+	MessageDigest digestInfo
+}
+
+type spcAttributeTypeAndOptionalValue struct {
+	Type asn1.ObjectIdentifier
+}
+
+type digestInfo struct {
+	DigestAlgorithm pkix.AlgorithmIdentifier
+	Digest          []byte
+}
+
+// samplePE returns a minimal 64-bit portable executable with a single
+// section. If table is not empty it is appended as the certificate table.
+func samplePE(table []byte) []byte {
+	const (
+		fileAlign    = 0x200
+		sectionRVA   = 0x1000
+		headerOffset = 0x40
+	)
+
+	var f bytes.Buffer
+	w := func(v interface{}) { binary.Write(&f, binary.LittleEndian, v) }
+
+	dos := make([]byte, headerOffset)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3C:], headerOffset)
+	f.Write(dos)
+
+	f.WriteString("PE\x00\x00")
+	w(uint16(0x8664))     // Machine
+	w(uint16(1))          // NumberOfSections
+	w([3]uint32{})        // TimeDateStamp, PointerToSymbolTable, NumberOfSymbols
+	w(uint16(112 + 16*8)) // SizeOfOptionalHeader
+	w(uint16(0x0022))     // Characteristics
+	w(uint16(0x20B))      // Magic
+	w([2]uint8{})         // Linker version
+	w([3]uint32{fileAlign, 0, 0})
+	w([2]uint32{sectionRVA, sectionRVA})
+	w(uint64(0x140000000)) // ImageBase
+	w([2]uint32{sectionRVA, fileAlign})
+	w([6]uint16{6, 0, 0, 0, 6, 0})
+	w(uint32(0))              // Win32VersionValue
+	w(uint32(2 * sectionRVA)) // SizeOfImage
+	w(uint32(fileAlign))      // SizeOfHeaders
+	w(uint32(0x12345678))     // CheckSum, which is excluded from the digest
+	w([2]uint16{3, 0})
+	w([4]uint64{0x100000, 0x1000, 0x100000, 0x1000})
+	w([2]uint32{0, 16})
+	for i := 0; i < 16; i++ {
+		if i == 4 && len(table) > 0 {
+			w([2]uint32{2 * fileAlign, uint32(len(table))})
+		} else {
+			w([2]uint32{})
+		}
+	}
+
+	var name [8]byte
+	copy(name[:], ".text")
+	w(name)
+	w([4]uint32{fileAlign, sectionRVA, fileAlign, fileAlign})
+	w([2]uint32{})
+	w([2]uint16{})
+	w(uint32(0x60000020))
+
+	for f.Len() < fileAlign {
+		f.WriteByte(0)
+	}
+	code := bytes.Repeat([]byte{0xCC}, fileAlign)
+	code[0] = 0xC3
+	f.Write(code)
+	f.Write(table)
+
+	return f.Bytes()
+}
+
+// imageDigest computes the Authenticode digest of a file produced by
+// samplePE.
+func imageDigest(file []byte) []byte {
+	const (
+		checksum  = 0x40 + 4 + 20 + 64
+		directory = 0x40 + 4 + 20 + 112 + 4*8
+		table     = 0x400
+	)
+	h := sha256.New()
+	h.Write(file[:checksum])
+	h.Write(file[checksum+4 : directory])
+	h.Write(file[directory+8 : table])
+	return h.Sum(nil)
+}
+
+func mustMarshal(t *testing.T, v interface{}, params ...string) []byte {
+	t.Helper()
+	var (
+		b   []byte
+		err error
+	)
+	if len(params) > 0 {
+		b, err = asn1.MarshalWithParams(v, params[0])
+	} else {
+		b, err = asn1.Marshal(v)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// sign returns a certificate table that holds an Authenticode signature
+// for the given digest, along with the signing certificate.
+func sign(t *testing.T, digest []byte, signedAt time.Time) ([]byte, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "Gentleman Automaton Test", Organization: []string{"Gentleman Automaton"}},
+		NotBefore:    signedAt.Add(-time.Hour),
+		NotAfter:     signedAt.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sha256Algorithm := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+
+	content := mustMarshal(t, spcIndirectDataContent{
+		Data: spcAttributeTypeAndOptionalValue{Type: oidSpcPEImageData},
+		MessageDigest: digestInfo{
+			DigestAlgorithm: sha256Algorithm,
+			Digest:          digest,
+		},
+	})
+	var inner asn1.RawValue
+	if _, err := asn1.Unmarshal(content, &inner); err != nil {
+		t.Fatal(err)
+	}
+	contentDigest := sha256.Sum256(inner.Bytes)
+
+	attrs := mustMarshal(t, []attribute{
+		{Type: oidContentType, Values: []asn1.RawValue{{FullBytes: mustMarshal(t, oidSpcIndirectDataContent)}}},
+		{Type: oidMessageDigest, Values: []asn1.RawValue{{FullBytes: mustMarshal(t, contentDigest[:])}}},
+	}, "set")
+	attrsDigest := sha256.Sum256(attrs)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, attrsDigest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A legacy counter-signature that carries the signing time
+	counterAttrs := mustMarshal(t, []attribute{
+		{Type: oidSigningTime, Values: []asn1.RawValue{{FullBytes: mustMarshal(t, signedAt, "utc")}}},
+	}, "set")
+	counter := mustMarshal(t, signerInfo{
+		Version:                   1,
+		IssuerAndSerialNumber:     issuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber},
+		DigestAlgorithm:           sha256Algorithm,
+		AuthenticatedAttributes:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: setContents(t, counterAttrs)},
+		DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidECPublicKey},
+		EncryptedDigest:           []byte{0},
+	})
+	unauth := mustMarshal(t, []attribute{
+		{Type: oidCounterSignature, Values: []asn1.RawValue{{FullBytes: counter}}},
+	}, "set")
+
+	sd := mustMarshal(t, signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Algorithm},
+		ContentInfo: contentInfo{
+			ContentType: oidSpcIndirectDataContent,
+			Content:     explicit(content),
+		},
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der},
+		SignerInfos: []signerInfo{{
+			Version:                   1,
+			IssuerAndSerialNumber:     issuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber},
+			DigestAlgorithm:           sha256Algorithm,
+			AuthenticatedAttributes:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: setContents(t, attrs)},
+			DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidECPublicKey},
+			EncryptedDigest:           signature,
+			UnauthenticatedAttributes: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: setContents(t, unauth)},
+		}},
+	})
+	pkcs7 := mustMarshal(t, contentInfo{
+		ContentType: oidSignedData,
+		Content:     explicit(sd),
+	})
+
+	// Wrap the signature in a WIN_CERTIFICATE structure
+	var table bytes.Buffer
+	binary.Write(&table, binary.LittleEndian, uint32(8+len(pkcs7)))
+	binary.Write(&table, binary.LittleEndian, uint16(0x0200))
+	binary.Write(&table, binary.LittleEndian, uint16(0x0002))
+	table.Write(pkcs7)
+	for table.Len()%8 != 0 {
+		table.WriteByte(0)
+	}
+
+	return table.Bytes(), cert
+}
+
+// setContents returns the contents of an encoded SET.
+func setContents(t *testing.T, set []byte) []byte {
+	t.Helper()
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(set, &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw.Bytes
+}
+
+// signedPE returns a signed sample file and its signing certificate.
+func signedPE(t *testing.T, signedAt time.Time) ([]byte, *x509.Certificate) {
+	t.Helper()
+
+	// The digest doesn't depend on the contents of the certificate table,
+	// only its location, so compute it with a placeholder of equal size.
+	placeholder, _ := sign(t, make([]byte, sha256.Size), signedAt)
+	digest := imageDigest(samplePE(placeholder))
+	table, cert := sign(t, digest, signedAt)
+	if len(table) != len(placeholder) {
+		// ECDSA signatures vary in length, so try again
+		return signedPE(t, signedAt)
+	}
+	return samplePE(table), cert
+}
+
+func TestParse(t *testing.T) {
+	signedAt := time.Date(2024, 3, 14, 15, 9, 26, 0, time.UTC)
+	file, cert := signedPE(t, signedAt)
+
+	sig, err := authenticode.Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !sig.DigestMatches() {
+		t.Errorf("the image digest does not match: recorded %x, computed %x", sig.Digest, sig.ImageDigest)
+	}
+	if !sig.SignerVerified {
+		t.Errorf("the signer's signature could not be verified")
+	}
+	if !sig.Valid() {
+		t.Errorf("the signature is not valid")
+	}
+	if !strings.Contains(sig.Subject, "CN=Gentleman Automaton Test") {
+		t.Errorf("unexpected subject: %s", sig.Subject)
+	}
+	if sig.Issuer != sig.Subject {
+		t.Errorf("unexpected issuer for a self-signed certificate: %s", sig.Issuer)
+	}
+	thumbprint := sha1.Sum(cert.Raw)
+	if want := strings.ToUpper(hex.EncodeToString(thumbprint[:])); sig.Thumbprint != want {
+		t.Errorf("unexpected thumbprint: got %s, want %s", sig.Thumbprint, want)
+	}
+	if !sig.Timestamp.Equal(signedAt) {
+		t.Errorf("unexpected timestamp: got %s, want %s", sig.Timestamp, signedAt)
+	}
+}
+
+func TestParseTampered(t *testing.T) {
+	file, _ := signedPE(t, time.Now().UTC().Truncate(time.Second))
+	file[0x200+10] ^= 0xFF // Modify the code section
+
+	sig, err := authenticode.Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.DigestMatches() {
+		t.Errorf("the digest of a tampered image matches")
+	}
+	if sig.Valid() {
+		t.Errorf("the signature of a tampered image is valid")
+	}
+}
+
+func TestParseNotSigned(t *testing.T) {
+	file := samplePE(nil)
+	if _, err := authenticode.Parse(bytes.NewReader(file), int64(len(file))); !errors.Is(err, authenticode.ErrNotSigned) {
+		t.Errorf("expected ErrNotSigned, got %v", err)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	file, _ := signedPE(t, time.Now().UTC().Truncate(time.Second))
+	file = file[:len(file)-16] // Truncate the certificate table
+
+	if _, err := authenticode.Parse(bytes.NewReader(file), int64(len(file))); !errors.Is(err, authenticode.ErrMalformed) {
+		t.Errorf("expected ErrMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+// Package authenticode inspects the Authenticode signatures embedded in
+// portable executable (PE) files.
+//
+// It is implemented in pure Go and does not depend on the windows
+// cryptography APIs, so it can be used on any platform. It extracts the
+// signer, verifies that the signature covers the image as it exists on
+// disk and verifies the signer's signature over that claim. It does not
+// check whether the signing certificate chains to a trusted root.
+//
+// Files that are signed through a security catalog rather than an embedded
+// signature are reported as ErrNotSigned.
+package authenticode
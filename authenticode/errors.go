@@ -0,0 +1,17 @@
+package authenticode
+
+import "errors"
+
+var (
+	// ErrNotSigned is returned when a file does not contain an embedded
+	// signature.
+	ErrNotSigned = errors.New("the file does not contain an embedded signature")
+
+	// ErrMalformed is returned when a signature or the file that contains
+	// it is malformed.
+	ErrMalformed = errors.New("the signature is malformed")
+
+	// ErrUnsupportedAlgorithm is returned when a signature uses an
+	// algorithm that is not supported.
+	ErrUnsupportedAlgorithm = errors.New("the signature uses an unsupported algorithm")
+)
@@ -0,0 +1,154 @@
+package authenticode
+
+import (
+	"crypto"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	securityDirectoryIndex = 4      // IMAGE_DIRECTORY_ENTRY_SECURITY
+	certificateRevision2   = 0x0200 // WIN_CERT_REVISION_2_0
+	certificateTypePKCS7   = 0x0002 // WIN_CERT_TYPE_PKCS_SIGNED_DATA
+	certificateHeaderSize  = 8      // sizeof(WIN_CERTIFICATE) without data
+)
+
+// image describes the parts of a portable executable file that are
+// relevant to its Authenticode signature.
+type image struct {
+	r    io.ReaderAt
+	size int64
+
+	checksumOffset  int64 // File offset of the CheckSum field
+	directoryOffset int64 // File offset of the security directory entry
+	tableOffset     int64 // File offset of the certificate table
+	tableSize       int64
+}
+
+// readImage locates the checksum, security directory and certificate table
+// of the portable executable provided by r.
+func readImage(r io.ReaderAt, size int64) (img image, err error) {
+	img.r, img.size = r, size
+
+	var dos [64]byte
+	if err := readFull(r, dos[:], 0); err != nil {
+		return image{}, err
+	}
+	if dos[0] != 'M' || dos[1] != 'Z' {
+		return image{}, ErrMalformed
+	}
+	peOffset := int64(binary.LittleEndian.Uint32(dos[0x3C:]))
+
+	var header [4 + 20 + 2]byte // Signature, COFF header and Magic
+	if err := readFull(r, header[:], peOffset); err != nil {
+		return image{}, err
+	}
+	if string(header[:4]) != "PE\x00\x00" {
+		return image{}, ErrMalformed
+	}
+
+	optional := peOffset + 4 + 20
+	var countOffset, directories int64
+	switch binary.LittleEndian.Uint16(header[24:]) {
+	case 0x10B: // PE32
+		countOffset, directories = optional+92, optional+96
+	case 0x20B: // PE32+
+		countOffset, directories = optional+108, optional+112
+	default:
+		return image{}, ErrMalformed
+	}
+
+	var count [4]byte
+	if err := readFull(r, count[:], countOffset); err != nil {
+		return image{}, err
+	}
+	if binary.LittleEndian.Uint32(count[:]) <= securityDirectoryIndex {
+		return image{}, ErrNotSigned
+	}
+
+	img.checksumOffset = optional + 64
+	img.directoryOffset = directories + securityDirectoryIndex*8
+
+	var entry [8]byte
+	if err := readFull(r, entry[:], img.directoryOffset); err != nil {
+		return image{}, err
+	}
+
+	// Unlike other data directories, the security directory holds a file
+	// offset instead of a relative virtual address
+	img.tableOffset = int64(binary.LittleEndian.Uint32(entry[0:]))
+	img.tableSize = int64(binary.LittleEndian.Uint32(entry[4:]))
+	if img.tableOffset == 0 || img.tableSize == 0 {
+		return image{}, ErrNotSigned
+	}
+	if img.tableOffset < img.directoryOffset+8 || img.tableOffset+img.tableSize > size {
+		return image{}, ErrMalformed
+	}
+
+	return img, nil
+}
+
+// signedData returns the first PKCS #7 signed data structure in the
+// certificate table.
+func (img image) signedData() ([]byte, error) {
+	for offset := int64(0); offset+certificateHeaderSize <= img.tableSize; {
+		var header [certificateHeaderSize]byte
+		if err := readFull(img.r, header[:], img.tableOffset+offset); err != nil {
+			return nil, err
+		}
+		length := int64(binary.LittleEndian.Uint32(header[0:]))
+		revision := binary.LittleEndian.Uint16(header[4:])
+		kind := binary.LittleEndian.Uint16(header[6:])
+		if length < certificateHeaderSize || offset+length > img.tableSize {
+			return nil, ErrMalformed
+		}
+
+		if revision == certificateRevision2 && kind == certificateTypePKCS7 {
+			data := make([]byte, length-certificateHeaderSize)
+			if err := readFull(img.r, data, img.tableOffset+offset+certificateHeaderSize); err != nil {
+				return nil, err
+			}
+			return data, nil
+		}
+
+		// Entries are aligned on 8-byte boundaries
+		offset += (length + 7) &^ 7
+	}
+	return nil, ErrNotSigned
+}
+
+// digest computes the Authenticode digest of the image. The digest covers
+// the whole file except for the checksum, the security directory entry
+// and the certificate table itself.
+func (img image) digest(hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	h := hash.New()
+
+	ranges := [][2]int64{
+		{0, img.checksumOffset},
+		{img.checksumOffset + 4, img.directoryOffset},
+		{img.directoryOffset + 8, img.tableOffset},
+	}
+	for _, span := range ranges {
+		section := io.NewSectionReader(img.r, span[0], span[1]-span[0])
+		if _, err := io.Copy(h, section); err != nil {
+			return nil, err
+		}
+	}
+
+	return h.Sum(nil), nil
+}
+
+// readFull reads exactly len(b) bytes from r at offset.
+func readFull(r io.ReaderAt, b []byte, offset int64) error {
+	n, err := r.ReadAt(b, offset)
+	if n == len(b) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		return ErrMalformed
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package authenticode
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+)
+
+// Object identifiers used by Authenticode signatures.
+var (
+	oidSignedData             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidSpcIndirectDataContent = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 4}
+	oidMessageDigest          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidCounterSignature       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 6}
+	oidRFC3161CounterSign     = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 3, 3, 1}
+
+	oidMD5    = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 5}
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidRSA             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA1WithRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidECPublicKey     = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidECDSAWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+)
+
+// hashFromOID returns the hash function identified by oid.
+func hashFromOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidMD5):
+		return crypto.MD5, nil
+	case oid.Equal(oidSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, nil
+	default:
+		return 0, ErrUnsupportedAlgorithm
+	}
+}
+
+// signatureAlgorithm returns the x509 signature algorithm described by a
+// PKCS #7 digest algorithm and digest encryption algorithm.
+func signatureAlgorithm(hash crypto.Hash, encryption asn1.ObjectIdentifier) (x509.SignatureAlgorithm, error) {
+	switch {
+	case encryption.Equal(oidRSA):
+		switch hash {
+		case crypto.SHA1:
+			return x509.SHA1WithRSA, nil
+		case crypto.SHA256:
+			return x509.SHA256WithRSA, nil
+		case crypto.SHA384:
+			return x509.SHA384WithRSA, nil
+		case crypto.SHA512:
+			return x509.SHA512WithRSA, nil
+		}
+	case encryption.Equal(oidECPublicKey):
+		switch hash {
+		case crypto.SHA1:
+			return x509.ECDSAWithSHA1, nil
+		case crypto.SHA256:
+			return x509.ECDSAWithSHA256, nil
+		case crypto.SHA384:
+			return x509.ECDSAWithSHA384, nil
+		case crypto.SHA512:
+			return x509.ECDSAWithSHA512, nil
+		}
+	case encryption.Equal(oidSHA1WithRSA):
+		return x509.SHA1WithRSA, nil
+	case encryption.Equal(oidSHA256WithRSA):
+		return x509.SHA256WithRSA, nil
+	case encryption.Equal(oidSHA384WithRSA):
+		return x509.SHA384WithRSA, nil
+	case encryption.Equal(oidSHA512WithRSA):
+		return x509.SHA512WithRSA, nil
+	case encryption.Equal(oidECDSAWithSHA1):
+		return x509.ECDSAWithSHA1, nil
+	case encryption.Equal(oidECDSAWithSHA256):
+		return x509.ECDSAWithSHA256, nil
+	case encryption.Equal(oidECDSAWithSHA384):
+		return x509.ECDSAWithSHA384, nil
+	case encryption.Equal(oidECDSAWithSHA512):
+		return x509.ECDSAWithSHA512, nil
+	}
+	return x509.UnknownSignatureAlgorithm, ErrUnsupportedAlgorithm
+}
@@ -0,0 +1,107 @@
+package authenticode
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"time"
+)
+
+// contentInfo is a PKCS #7 ContentInfo structure.
+//
+// https://datatracker.ietf.org/doc/html/rfc2315#section-7
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// signedData is a PKCS #7 SignedData structure.
+//
+// https://datatracker.ietf.org/doc/html/rfc2315#section-9.1
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// signerInfo is a PKCS #7 SignerInfo structure.
+//
+// https://datatracker.ietf.org/doc/html/rfc2315#section-9.2
+type signerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerialNumber
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+	UnauthenticatedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// spcIndirectDataContent is the content signed by an Authenticode
+// signature. It holds the digest of the image.
+type spcIndirectDataContent struct {
+	Data          asn1.RawValue
+	MessageDigest digestInfo
+}
+
+type digestInfo struct {
+	DigestAlgorithm pkix.AlgorithmIdentifier
+	Digest          []byte
+}
+
+// tstInfo is the content of an RFC 3161 timestamp token.
+//
+// https://datatracker.ietf.org/doc/html/rfc3161#section-2.4.2
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint digestInfo
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+}
+
+// parseAttributes parses a set of attributes encoded with an implicit
+// context-specific tag.
+func parseAttributes(raw asn1.RawValue) (attrs []attribute, err error) {
+	if len(raw.Bytes) == 0 {
+		return nil, nil
+	}
+	rest := raw.Bytes
+	for len(rest) > 0 {
+		var attr attribute
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs, nil
+}
+
+// findAttribute returns the first value of the attribute with the given
+// type.
+func findAttribute(attrs []attribute, oid asn1.ObjectIdentifier) (value []byte, ok bool) {
+	for _, attr := range attrs {
+		if !attr.Type.Equal(oid) {
+			continue
+		}
+		var raw asn1.RawValue
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &raw); err != nil {
+			return nil, false
+		}
+		return raw.FullBytes, true
+	}
+	return nil, false
+}
@@ -0,0 +1,286 @@
+package authenticode
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	// Register the hash functions used by Authenticode signatures
+	_ "crypto/md5"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// Signature describes an embedded Authenticode signature.
+type Signature struct {
+	// Signer is the certificate of the signer. Certificates holds every
+	// certificate included in the signature.
+	Signer       *x509.Certificate
+	Certificates []*x509.Certificate
+
+	Subject    string // The subject of the signer's certificate
+	Issuer     string // The issuer of the signer's certificate
+	Thumbprint string // The SHA-1 hash of the signer's certificate in hex
+
+	// Timestamp is the time at which the signature was made according to
+	// a counter-signature. It is zero if the signature was not timestamped.
+	Timestamp time.Time
+
+	// DigestAlgorithm is the hash function used to compute Digest.
+	DigestAlgorithm crypto.Hash
+
+	// Digest is the image digest recorded in the signature. ImageDigest is
+	// the digest of the image as it exists.
+	Digest      []byte
+	ImageDigest []byte
+
+	// SignerVerified is true if the signer's signature over the recorded
+	// digest is valid. It does not imply that the signer is trusted.
+	SignerVerified bool
+}
+
+// DigestMatches returns true if the digest recorded in the signature
+// matches the image.
+func (sig Signature) DigestMatches() bool {
+	return len(sig.Digest) > 0 && bytes.Equal(sig.Digest, sig.ImageDigest)
+}
+
+// Valid returns true if the signature covers the image and was made by the
+// holder of the signer's certificate.
+func (sig Signature) Valid() bool {
+	return sig.DigestMatches() && sig.SignerVerified
+}
+
+// Read returns the embedded signature of the portable executable file at
+// path.
+//
+// It returns ErrNotSigned if the file does not contain an embedded
+// signature.
+func Read(path string) (Signature, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Signature{}, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return Parse(f, fi.Size())
+}
+
+// Parse returns the embedded signature of the portable executable file of
+// the given size provided by r.
+//
+// It returns ErrNotSigned if the file does not contain an embedded
+// signature.
+func Parse(r io.ReaderAt, size int64) (Signature, error) {
+	img, err := readImage(r, size)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	data, err := img.signedData()
+	if err != nil {
+		return Signature{}, err
+	}
+
+	sig, err := parseSignedData(data)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	if sig.ImageDigest, err = img.digest(sig.DigestAlgorithm); err != nil {
+		return Signature{}, err
+	}
+
+	return sig, nil
+}
+
+// parseSignedData parses a PKCS #7 signed data structure that holds an
+// Authenticode signature.
+func parseSignedData(data []byte) (sig Signature, err error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(data, &ci); err != nil {
+		return Signature{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return Signature{}, fmt.Errorf("%w: unexpected content type %s", ErrMalformed, ci.ContentType)
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return Signature{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if !sd.ContentInfo.ContentType.Equal(oidSpcIndirectDataContent) {
+		return Signature{}, fmt.Errorf("%w: unexpected signed content type %s", ErrMalformed, sd.ContentInfo.ContentType)
+	}
+	if len(sd.SignerInfos) != 1 {
+		return Signature{}, fmt.Errorf("%w: expected one signer, found %d", ErrMalformed, len(sd.SignerInfos))
+	}
+	signer := sd.SignerInfos[0]
+
+	// Extract the image digest from the signed content
+	var content spcIndirectDataContent
+	if _, err := asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &content); err != nil {
+		return Signature{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if sig.DigestAlgorithm, err = hashFromOID(content.MessageDigest.DigestAlgorithm.Algorithm); err != nil {
+		return Signature{}, err
+	}
+	sig.Digest = content.MessageDigest.Digest
+
+	// Identify the signer
+	if len(sd.Certificates.Bytes) > 0 {
+		if sig.Certificates, err = x509.ParseCertificates(sd.Certificates.Bytes); err != nil {
+			return Signature{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+	}
+	sig.Signer = findCertificate(sig.Certificates, signer.IssuerAndSerialNumber)
+	if sig.Signer == nil {
+		return Signature{}, fmt.Errorf("%w: the signer's certificate is missing", ErrMalformed)
+	}
+	sig.Subject = sig.Signer.Subject.String()
+	sig.Issuer = sig.Signer.Issuer.String()
+	thumbprint := sha1.Sum(sig.Signer.Raw)
+	sig.Thumbprint = strings.ToUpper(hex.EncodeToString(thumbprint[:]))
+
+	sig.SignerVerified = verifySigner(signer, sig.Signer, sd.ContentInfo.Content.Bytes) == nil
+	sig.Timestamp = timestamp(signer)
+
+	return sig, nil
+}
+
+// findCertificate returns the certificate with the given issuer and serial
+// number.
+func findCertificate(certs []*x509.Certificate, id issuerAndSerialNumber) *x509.Certificate {
+	for _, cert := range certs {
+		if cert.SerialNumber.Cmp(id.SerialNumber) == 0 && bytes.Equal(cert.RawIssuer, id.Issuer.FullBytes) {
+			return cert
+		}
+	}
+	return nil
+}
+
+// verifySigner verifies that signer signed content with the key of cert.
+//
+// The signer signs its authenticated attributes, which must include a
+// digest of the content. For Authenticode the content digest covers the
+// value of the SpcIndirectDataContent sequence, excluding its tag and
+// length.
+func verifySigner(signer signerInfo, cert *x509.Certificate, content []byte) error {
+	hash, err := hashFromOID(signer.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+	algorithm, err := signatureAlgorithm(hash, signer.DigestEncryptionAlgorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	attrs, err := parseAttributes(signer.AuthenticatedAttributes)
+	if err != nil {
+		return err
+	}
+	value, ok := findAttribute(attrs, oidMessageDigest)
+	if !ok {
+		return fmt.Errorf("%w: the message digest attribute is missing", ErrMalformed)
+	}
+	var recorded []byte
+	if _, err := asn1.Unmarshal(value, &recorded); err != nil {
+		return err
+	}
+
+	var inner asn1.RawValue
+	if _, err := asn1.Unmarshal(content, &inner); err != nil {
+		return err
+	}
+	h := hash.New()
+	h.Write(inner.Bytes)
+	if !bytes.Equal(h.Sum(nil), recorded) {
+		return fmt.Errorf("%w: the message digest does not match the signed content", ErrMalformed)
+	}
+
+	// The attributes are signed as an explicit SET OF rather than with the
+	// implicit tag they are stored with
+	signed := append([]byte(nil), signer.AuthenticatedAttributes.FullBytes...)
+	signed[0] = 0x31
+
+	return cert.CheckSignature(algorithm, signed, signer.EncryptedDigest)
+}
+
+// timestamp returns the signing time recorded by a counter-signature of
+// signer. It supports both legacy and RFC 3161 counter-signatures.
+//
+// It returns a zero value if the signer was not counter-signed.
+func timestamp(signer signerInfo) time.Time {
+	attrs, err := parseAttributes(signer.UnauthenticatedAttributes)
+	if err != nil {
+		return time.Time{}
+	}
+
+	if value, ok := findAttribute(attrs, oidCounterSignature); ok {
+		var counter signerInfo
+		if _, err := asn1.Unmarshal(value, &counter); err == nil {
+			if t, ok := signingTime(counter); ok {
+				return t
+			}
+		}
+	}
+
+	if value, ok := findAttribute(attrs, oidRFC3161CounterSign); ok {
+		if t, ok := tokenTime(value); ok {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// signingTime returns the value of the signing time attribute of signer.
+func signingTime(signer signerInfo) (time.Time, bool) {
+	attrs, err := parseAttributes(signer.AuthenticatedAttributes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	value, ok := findAttribute(attrs, oidSigningTime)
+	if !ok {
+		return time.Time{}, false
+	}
+	var t time.Time
+	if _, err := asn1.Unmarshal(value, &t); err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// tokenTime returns the generation time of an RFC 3161 timestamp token.
+func tokenTime(token []byte) (time.Time, bool) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(token, &ci); err != nil || !ci.ContentType.Equal(oidSignedData) {
+		return time.Time{}, false
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return time.Time{}, false
+	}
+	var encoded []byte
+	if _, err := asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &encoded); err != nil {
+		return time.Time{}, false
+	}
+	var info tstInfo
+	if _, err := asn1.Unmarshal(encoded, &info); err != nil {
+		return time.Time{}, false
+	}
+	return info.GenTime, true
+}
@@ -0,0 +1,270 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/gentlemanautomaton/winproc/fileversion"
+)
+
+// immutableCollectors holds the collectors that gather information which
+// doesn't change after a process has been created.
+const immutableCollectors = CollectCommands | CollectSessions | CollectUsers | CollectPackages | CollectFileVersion | CollectSignatures
+
+// Cache remembers information about processes that doesn't change after
+// they have been created, so that it needn't be collected again by later
+// calls to List. Entries are keyed by the unique ID of each process.
+//
+// The command line, path, arguments, session, user, package and image
+// information of each process is cached. All other information is
+// collected each time.
+//
+// Entries are discarded once their processes are no longer running.
+//
+// It is safe for concurrent use.
+type Cache struct {
+	mutex   sync.Mutex
+	entries map[UniqueID]cacheEntry
+}
+
+// cacheEntry holds the immutable information about a process.
+type cacheEntry struct {
+	collected   Collector // The collectors that have been cached
+	CommandLine string
+	Path        string
+	Args        []string
+	SessionID   uint32
+	User        User
+	Package     Package
+	ImagePath   string
+	Version     fileversion.Info
+	Signing     Signing
+}
+
+// NewCache returns a new process information cache.
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[UniqueID]cacheEntry),
+	}
+}
+
+// Collect returns a collection option that collects c, using the cache
+// for information that doesn't change.
+//
+// Process times are always collected, because they are needed to identify
+// each process.
+func (cache *Cache) Collect(c Collector) CollectionOption {
+	return cachedCollector{cache: cache, collectors: c}
+}
+
+// wrap returns a copy of options in which each collector makes use of the
+// cache.
+func (cache *Cache) wrap(options []CollectionOption) []CollectionOption {
+	wrapped := make([]CollectionOption, len(options))
+	for i, option := range options {
+		switch o := option.(type) {
+		case Collector:
+			if o&^bulkCollectors != 0 {
+				option = cache.Collect(o)
+			}
+		case collectorSet:
+			option = cachedCollector{cache: cache, collectors: o.builtin, custom: o.custom}
+		}
+		wrapped[i] = option
+	}
+	return wrapped
+}
+
+// Len returns the number of processes in the cache.
+func (cache *Cache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return len(cache.entries)
+}
+
+// lookup returns the cache entry for id.
+func (cache *Cache) lookup(id UniqueID) (entry cacheEntry, ok bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	entry, ok = cache.entries[id]
+	return
+}
+
+// store records the information in proc that was gathered by the
+// collectors in c.
+func (cache *Cache) store(id UniqueID, c Collector, proc *Process) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry := cache.entries[id]
+	if c.Contains(CollectCommands) {
+		entry.CommandLine = proc.CommandLine
+		entry.Path = proc.Path
+		entry.Args = slices.Clone(proc.Args)
+	}
+	if c.Contains(CollectSessions) {
+		entry.SessionID = proc.SessionID
+	}
+	if c.Contains(CollectUsers) {
+		entry.User = proc.User
+	}
+	if c.Contains(CollectPackages) {
+		entry.Package = proc.Package
+	}
+	if c&(CollectFileVersion|CollectSignatures) != 0 {
+		entry.ImagePath = proc.ImagePath
+	}
+	if c.Contains(CollectFileVersion) {
+		entry.Version = proc.Version
+	}
+	if c.Contains(CollectSignatures) {
+		entry.Signing = proc.Signing
+	}
+	entry.collected |= c
+	cache.entries[id] = entry
+}
+
+// prune removes entries for processes that are not present in procs.
+func (cache *Cache) prune(procs []Process, running map[ID]UniqueID) {
+	present := make(map[ID]bool, len(procs))
+	for i := range procs {
+		present[procs[i].ID] = true
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for id := range cache.entries {
+		if !present[id.ID] {
+			delete(cache.entries, id)
+		} else if current, ok := running[id.ID]; ok && current != id {
+			// The process ID has been reused
+			delete(cache.entries, id)
+		}
+	}
+}
+
+// apply copies the information collected by c from the entry to proc.
+func (entry cacheEntry) apply(c Collector, proc *Process) {
+	if c.Contains(CollectCommands) {
+		proc.CommandLine = entry.CommandLine
+		proc.Path = entry.Path
+		proc.Args = slices.Clone(entry.Args)
+	}
+	if c.Contains(CollectSessions) {
+		proc.SessionID = entry.SessionID
+	}
+	if c.Contains(CollectUsers) {
+		proc.User = entry.User
+	}
+	if c.Contains(CollectPackages) {
+		proc.Package = entry.Package
+	}
+	if c&(CollectFileVersion|CollectSignatures) != 0 {
+		proc.ImagePath = entry.ImagePath
+	}
+	if c.Contains(CollectFileVersion) {
+		proc.Version = entry.Version
+	}
+	if c.Contains(CollectSignatures) {
+		proc.Signing = entry.Signing
+	}
+}
+
+// cachedCollector is a collector that makes use of a cache. Its custom
+// collectors are always applied, because their results can't be cached.
+type cachedCollector struct {
+	cache      *Cache
+	collectors Collector
+	custom     []CustomCollector
+}
+
+// Apply applies the cached collector to the collection.
+func (cc cachedCollector) Apply(col *Collection) {
+	var (
+		mutex   sync.Mutex
+		running = make(map[ID]UniqueID)
+	)
+
+	// Bulk collectors maintain caches of their own
+	cc.collectors.collectBulk(col)
+	cc.collectors &^= bulkCollectors
+
+	col.forEach(cc.collectors|CollectTimes, func(proc *Process) {
+		ref, err := col.open(proc.ID)
+		if err != nil {
+			proc.fail(cc.collectors|CollectTimes, "open", err)
+			return
+		}
+		defer ref.Close()
+
+		defer collectCustom(cc.custom, ref, proc)
+
+		// Identify the process
+		CollectTimes.collectRef(ref, proc)
+		if proc.Times.Creation.IsZero() {
+			(cc.collectors &^ CollectTimes).collectRef(ref, proc)
+			return
+		}
+		id := proc.UniqueID()
+
+		mutex.Lock()
+		running[proc.ID] = id
+		mutex.Unlock()
+
+		// Use whatever immutable information has already been collected
+		cached := cc.collectors & immutableCollectors
+		entry, ok := cc.cache.lookup(id)
+		if ok {
+			cached &= entry.collected
+			entry.apply(cached, proc)
+		} else {
+			cached = 0
+		}
+
+		// Collect everything else
+		remaining := cc.collectors &^ cached &^ CollectTimes
+		failures := len(proc.Errors)
+		remaining.collectRef(ref, proc)
+
+		// Remember the immutable information that was collected without
+		// error
+		store := remaining & immutableCollectors
+		for _, err := range proc.Errors[failures:] {
+			store &^= err.Collector
+		}
+		if store != 0 {
+			cc.cache.store(id, store, proc)
+		}
+	})
+
+	// Processes that were abandoned due to timeouts may still be running
+	mutex.Lock()
+	defer mutex.Unlock()
+	cc.cache.prune(col.Procs, running)
+}
+
+// Merge attempts to merge the cached collector with the next option. It
+// returns true if successful.
+func (cc cachedCollector) Merge(next CollectionOption) (merged CollectionOption, ok bool) {
+	switch n := next.(type) {
+	case cachedCollector:
+		if n.cache != cc.cache {
+			return nil, false
+		}
+		cc.collectors |= n.collectors
+		cc.custom = append(cc.custom[:len(cc.custom):len(cc.custom)], n.custom...)
+		return cc, true
+	case collectorSet:
+		cc.collectors |= n.builtin
+		cc.custom = append(cc.custom[:len(cc.custom):len(cc.custom)], n.custom...)
+		return cc, true
+	case Collector:
+		cc.collectors |= n
+		return cc, true
+	}
+	return nil, false
+}
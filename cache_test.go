@@ -0,0 +1,58 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+func TestCache(t *testing.T) {
+	cache := winproc.NewCache()
+	self := winproc.Include(winproc.MatchID(winproc.ID(os.Getpid())))
+
+	first, err := winproc.List(self, cache.Collect(winproc.CollectCommands|winproc.CollectUsers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 {
+		t.Fatalf("expected 1 process, found %d", len(first))
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected 1 cache entry, found %d", cache.Len())
+	}
+
+	second, err := winproc.List(self, cache.Collect(winproc.CollectCommands|winproc.CollectUsers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != 1 {
+		t.Fatalf("expected 1 process, found %d", len(second))
+	}
+	if first[0].CommandLine != second[0].CommandLine {
+		t.Errorf("command line changed from %q to %q", first[0].CommandLine, second[0].CommandLine)
+	}
+	if first[0].User != second[0].User {
+		t.Errorf("user changed from %v to %v", first[0].User, second[0].User)
+	}
+
+	// Changes made by callers must not leak into the cache
+	if len(second[0].Args) > 0 {
+		original := second[0].Args[0]
+		second[0].Args[0] = "modified"
+
+		third, err := winproc.List(self, cache.Collect(winproc.CollectCommands))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(third) != 1 {
+			t.Fatalf("expected 1 process, found %d", len(third))
+		}
+		if third[0].Args[0] != original {
+			t.Errorf("cached argument changed from %q to %q", original, third[0].Args[0])
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+// columns describes optional information that is displayed for each
+// process.
+type columns struct {
+	Services bool
+}
+
+// options returns the collection options needed to display the columns.
+func (c columns) options() (opts []winproc.CollectionOption) {
+	if c.Services {
+		opts = append(opts, winproc.CollectServices)
+	}
+	return
+}
+
+// format returns a string representation of proc that includes the
+// columns.
+func (c columns) format(proc winproc.Process) string {
+	value := proc.String()
+	if c.Services && len(proc.Services) > 0 {
+		names := make([]string, len(proc.Services))
+		for i, service := range proc.Services {
+			names[i] = service.Name
+		}
+		value += " [services: " + strings.Join(names, ", ") + "]"
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+// KillCmd terminates windows processes.
+type KillCmd struct {
+	IncludePIDs        []uint32 `kong:"optional,name='pid',help='Terminate processes with a particular ID.'"`
+	IncludeNames       []string `kong:"optional,name='name',help='Terminate processes with a particular image name, such as notepad.exe. The whole name must match.'"`
+	IncludeDescendents bool     `kong:"optional,name='descendents',short='d',help='Terminate descendants of matching processes.'"`
+	ExitCode           uint32   `kong:"optional,name='exit-code',default='1',help='The exit code to terminate processes with.'"`
+	AllowCritical      bool     `kong:"optional,name='allow-critical',help='Permit termination of processes that are critical to the system.'"`
+	AllowProtected     bool     `kong:"optional,name='allow-protected',help='Permit termination of protected system processes.'"`
+}
+
+// Run executes the kill command.
+func (cmd KillCmd) Run(ctx context.Context) error {
+	if len(cmd.IncludePIDs) == 0 && len(cmd.IncludeNames) == 0 {
+		return errors.New("at least one process ID or name must be provided")
+	}
+
+	// Names must match exactly, so that a short name can't accidentally
+	// select unrelated processes
+	var filters []winproc.Filter
+	for _, pid := range cmd.IncludePIDs {
+		filters = append(filters, winproc.MatchID(winproc.ID(pid)))
+	}
+	for _, name := range cmd.IncludeNames {
+		filters = append(filters, winproc.EqualsName(name))
+	}
+
+	opts := []winproc.CollectionOption{winproc.Include(winproc.MatchAny(filters...))}
+	if cmd.IncludeDescendents {
+		opts = append(opts, winproc.IncludeDescendants)
+	}
+	opts = append(opts, winproc.CollectCommands, winproc.CollectSessions, winproc.CollectUsers, winproc.CollectTimes, winproc.CollectCriticality)
+
+	procs, err := winproc.List(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve process list: %v", err)
+	}
+	if len(procs) == 0 {
+		return errors.New("no matching processes were found")
+	}
+
+	policy := winproc.Policy{
+		AllowCritical:  cmd.AllowCritical,
+		AllowProtected: cmd.AllowProtected,
+	}
+
+	outcomes, err := policy.TerminateTree(cmd.ExitCode)(winproc.Tree(procs))
+	for _, outcome := range outcomes {
+		switch {
+		case outcome.Err != nil:
+			fmt.Printf("%s: %v\n", outcome.Process, outcome.Err)
+		case outcome.Exited:
+			fmt.Printf("%s: already exited\n", outcome.Process)
+		default:
+			fmt.Printf("%s: terminated\n", outcome.Process)
+		}
+	}
+
+	var actionErr *winproc.TreeActionError
+	if errors.As(err, &actionErr) {
+		return errors.New("one or more processes could not be terminated")
+	}
+	return err
+}
@@ -13,17 +13,20 @@ type ListCmd struct {
 	IncludeNames       []string `kong:"optional,name='name',help='Include processes with a particular name.'"`
 	IncludeAncestors   bool     `kong:"optional,name='ancestors',short='a',help='Include ancestors of matching processes.'"`
 	IncludeDescendents bool     `kong:"optional,name='descendents',short='d',help='Include descendants of matching processes.'"`
+	Services           bool     `kong:"optional,name='services',help='Show the services hosted by each process.'"`
 }
 
 // Run executes the list command.
 func (cmd ListCmd) Run(ctx context.Context) error {
+	cols := columns{Services: cmd.Services}
 	opts := makeOptions(cmd.IncludePIDs, cmd.IncludeNames, cmd.IncludeAncestors, cmd.IncludeDescendents)
+	opts = append(opts, cols.options()...)
 	procs, err := winproc.List(opts...)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve process list: %v\n", err)
 	}
 	for _, proc := range procs {
-		fmt.Printf("%s\n", proc)
+		fmt.Printf("%s\n", cols.format(proc))
 	}
 	return nil
 }
@@ -17,6 +17,7 @@ func main() {
 		List  ListCmd  `kong:"cmd,help='Provides a list view of the windows process list.'"`
 		Tree  TreeCmd  `kong:"cmd,help='Provides a tree view of the windows process list.'"`
 		Watch WatchCmd `kong:"cmd,help='Watches the windows process list.'"`
+		Kill  KillCmd  `kong:"cmd,help='Terminates windows processes.'"`
 	}
 
 	parser := kong.Must(&cli,
@@ -14,22 +14,31 @@ type TreeCmd struct {
 	IncludeNames       []string `kong:"optional,name='name',help='Include processes with a particular name.'"`
 	IncludeAncestors   bool     `kong:"optional,name='ancestors',short='a',help='Include ancestors of matching processes.'"`
 	IncludeDescendents bool     `kong:"optional,name='descendents',short='d',help='Include descendants of matching processes.'"`
+	Services           bool     `kong:"optional,name='services',help='Show the services hosted by each process.'"`
+	AttachConsoleHosts bool     `kong:"optional,name='conhost',help='Show console hosts beneath the console applications they serve.'"`
 }
 
 // Run executes the tree command.
 func (cmd TreeCmd) Run(ctx context.Context) error {
+	cols := columns{Services: cmd.Services}
 	opts := makeOptions(cmd.IncludePIDs, cmd.IncludeNames, cmd.IncludeAncestors, cmd.IncludeDescendents)
+	opts = append(opts, cols.options()...)
+	var treeOpts []winproc.TreeOption
+	if cmd.AttachConsoleHosts {
+		opts = append(opts, winproc.CollectConsoleHosts)
+		treeOpts = append(treeOpts, winproc.AttachConsoleHosts)
+	}
 	procs, err := winproc.List(opts...)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve process tree: %v\n", err)
 	}
-	printChildren(0, winproc.Tree(procs))
+	printChildren(0, winproc.Tree(procs, treeOpts...), cols)
 	return nil
 }
 
-func printChildren(depth int, nodes []winproc.Node) {
+func printChildren(depth int, nodes []winproc.Node, cols columns) {
 	for _, node := range nodes {
-		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), node.Process)
-		printChildren(depth+1, node.Children)
+		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), cols.format(node.Process))
+		printChildren(depth+1, node.Children, cols)
 	}
 }
@@ -14,12 +14,15 @@ type WatchCmd struct {
 	IncludeNames       []string      `kong:"optional,name='name',help='Include processes with a particular name.'"`
 	IncludeAncestors   bool          `kong:"optional,name='ancestors',short='a',help='Include ancestors of matching processes.'"`
 	IncludeDescendents bool          `kong:"optional,name='descendents',short='d',help='Include descendants of matching processes.'"`
+	Services           bool          `kong:"optional,name='services',help='Show the services hosted by each process.'"`
 	Interval           time.Duration `kong:"optional,name='interval',short='i',default='1s',help='Interval between updates.'"`
 }
 
 // Run executes the watch command.
 func (cmd WatchCmd) Run(ctx context.Context) error {
+	cols := columns{Services: cmd.Services}
 	opts := makeOptions(cmd.IncludePIDs, cmd.IncludeNames, cmd.IncludeAncestors, cmd.IncludeDescendents)
+	opts = append(opts, cols.options()...)
 	for cs := range winproc.Watch(ctx, cmd.Interval, 8, opts...) {
 		if cs.Err != nil {
 			switch cs.Err {
@@ -32,9 +35,9 @@ func (cmd WatchCmd) Run(ctx context.Context) error {
 
 		for _, change := range cs.Changes {
 			if change.Removed {
-				fmt.Printf("STOP: %s\n", change.Process)
+				fmt.Printf("STOP: %s\n", cols.format(change.Process))
 			} else {
-				fmt.Printf("START: %s\n", change.Process)
+				fmt.Printf("START: %s\n", cols.format(change.Process))
 			}
 		}
 	}
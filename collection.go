@@ -3,10 +3,22 @@
 
 package winproc
 
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
 // Collection holds interim processing information while collecting processes.
 type Collection struct {
 	Procs    []Process
 	Excluded []bool // Excluded[i] corresponds to Procs[i]
+
+	Context        context.Context // Cancels collection when done, if not nil
+	Workers        int             // Maximum number of processes examined at once, if positive
+	ProcessTimeout time.Duration   // Maximum time spent examining each process, if positive
+	Debug          bool            // Use SeDebugPrivilege when access is denied
 }
 
 // A CollectionOption is capable of applying its settings to a collection.
@@ -20,3 +32,122 @@ type CollectionOption interface {
 type MergableCollectionOption interface {
 	Merge(next CollectionOption) (merged CollectionOption, ok bool)
 }
+
+// MaxWorkers is a collection option that limits the number of processes
+// that collectors will examine at the same time. By default there is no
+// limit.
+//
+// It only affects collectors that follow it.
+type MaxWorkers int
+
+// Apply applies the worker limit to the collection.
+func (n MaxWorkers) Apply(col *Collection) {
+	col.Workers = int(n)
+}
+
+// ProcessTimeout is a collection option that limits the amount of time
+// that each collector will spend examining a single process. By default
+// there is no limit.
+//
+// When a process takes too long, the collector abandons it and records
+// a timeout in its Errors field. Any information that was collected for
+// the process by that collector is discarded.
+//
+// It only affects collectors that follow it.
+type ProcessTimeout time.Duration
+
+// Apply applies the process timeout to the collection.
+func (d ProcessTimeout) Apply(col *Collection) {
+	col.ProcessTimeout = time.Duration(d)
+}
+
+// context returns the context of the collection.
+func (col *Collection) context() context.Context {
+	if col.Context == nil {
+		return context.Background()
+	}
+	return col.Context
+}
+
+// forEach calls fn for each process in the collection that has not been
+// excluded. It honors the context, worker limit and process timeout of the
+// collection.
+//
+// When a process cannot be examined in time, a collection error affecting
+// the collectors in c is recorded for it.
+func (col *Collection) forEach(c Collector, fn func(proc *Process)) {
+	ctx := col.context()
+
+	workers := col.Workers
+	if workers <= 0 || workers > len(col.Procs) {
+		workers = len(col.Procs)
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				col.examine(ctx, c, i, fn)
+			}
+		}()
+	}
+
+	for i := range col.Procs {
+		if !col.Excluded[i] {
+			work <- i
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+// examine calls fn for the process at index i.
+func (col *Collection) examine(ctx context.Context, c Collector, i int, fn func(proc *Process)) {
+	fail := func(err error) {
+		if err == context.DeadlineExceeded {
+			err = ErrTimeout
+		}
+		col.Procs[i].fail(c, "collect", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		fail(err)
+		return
+	}
+
+	// Without a deadline there's no need to guard against stragglers
+	if col.ProcessTimeout <= 0 && ctx.Done() == nil {
+		fn(&col.Procs[i])
+		return
+	}
+
+	if col.ProcessTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, col.ProcessTimeout)
+		defer cancel()
+	}
+
+	// Work on a copy of the process so that a call that never returns
+	// can be abandoned safely. The errors slice is clipped and the
+	// extensions are cloned so that changes made to the copy don't share
+	// memory with the original.
+	proc := col.Procs[i]
+	proc.Errors = proc.Errors[:len(proc.Errors):len(proc.Errors)]
+	proc.Extensions = maps.Clone(proc.Extensions)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(&proc)
+	}()
+
+	select {
+	case <-done:
+		col.Procs[i] = proc
+	case <-ctx.Done():
+		fail(ctx.Err())
+	}
+}
@@ -0,0 +1,137 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/ntstatus"
+	"golang.org/x/sys/windows"
+)
+
+// CollectionError records a failure to collect information about a process.
+//
+// Collection errors can be tested against ErrAccessDenied, ErrProcessGone,
+// ErrUnsupported and ErrTimeout with errors.Is to determine the general
+// cause of the failure.
+type CollectionError struct {
+	Collector Collector // The collectors that were affected, if any
+	Op        string    // The operation that failed, such as "open" or "user"
+	Err       error     // The underlying error
+}
+
+// Error returns a string representation of the error.
+func (e CollectionError) Error() string {
+	if e.Collector == 0 {
+		return fmt.Sprintf("%s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", e.Op, e.Collector, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e CollectionError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is the category of error that e belongs to.
+func (e CollectionError) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target
+}
+
+// Kind returns the category of error that e belongs to. It returns
+// ErrAccessDenied, ErrProcessGone, ErrUnsupported, ErrTimeout or nil if the
+// error could not be categorized.
+func (e CollectionError) Kind() error {
+	return errorKind(e.Err)
+}
+
+// errorKind categorizes err.
+func errorKind(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, kind := range []error{ErrAccessDenied, ErrProcessGone, ErrUnsupported, ErrTimeout} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+
+	if errors.Is(err, ErrProcessReplaced) {
+		return ErrProcessGone
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+
+	// Missing system calls are reported as DLL errors by the lazy loader
+	var dllErr *windows.DLLError
+	if errors.As(err, &dllErr) {
+		return ErrUnsupported
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case windows.ERROR_ACCESS_DENIED:
+			return ErrAccessDenied
+		case windows.ERROR_INVALID_PARAMETER, windows.ERROR_INVALID_HANDLE:
+			// OpenProcess reports an invalid parameter when the process
+			// ID no longer refers to a running process.
+			return ErrProcessGone
+		case windows.ERROR_NOT_SUPPORTED, windows.ERROR_CALL_NOT_IMPLEMENTED, windows.ERROR_PROC_NOT_FOUND:
+			return ErrUnsupported
+		}
+		return nil
+	}
+
+	var status ntstatus.Value
+	if errors.As(err, &status) {
+		switch status {
+		case ntstatus.AccessDenied:
+			return ErrAccessDenied
+		case ntstatus.ProcessIsTerminating:
+			return ErrProcessGone
+		case ntstatus.InvalidInfoClass, ntstatus.NotSupported:
+			return ErrUnsupported
+		}
+	}
+
+	return nil
+}
+
+// collectorNames holds the names of each collector, in bit order.
+var collectorNames = []string{
+	"commands",
+	"sessions",
+	"users",
+	"times",
+	"criticality",
+	"jobs",
+	"console hosts",
+	"packages",
+	"file version",
+	"signatures",
+	"cycle time",
+	"hashes",
+	"services",
+	"windows",
+}
+
+// String returns a string representation of the collectors in c.
+func (c Collector) String() string {
+	var names []string
+	for i, name := range collectorNames {
+		if c&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,67 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/ntstatus"
+)
+
+func TestCollectionErrorKind(t *testing.T) {
+	tests := []struct {
+		Err  error
+		Kind error
+	}{
+		{syscall.Errno(5), winproc.ErrAccessDenied}, // ERROR_ACCESS_DENIED
+		{syscall.Errno(87), winproc.ErrProcessGone}, // ERROR_INVALID_PARAMETER
+		{syscall.Errno(50), winproc.ErrUnsupported}, // ERROR_NOT_SUPPORTED
+		{ntstatus.AccessDenied, winproc.ErrAccessDenied},
+		{ntstatus.ProcessIsTerminating, winproc.ErrProcessGone},
+		{ntstatus.InvalidInfoClass, winproc.ErrUnsupported},
+		{winproc.ErrTimeout, winproc.ErrTimeout},
+		{syscall.Errno(2), nil}, // ERROR_FILE_NOT_FOUND
+	}
+	for _, test := range tests {
+		err := winproc.CollectionError{Collector: winproc.CollectUsers, Op: "user", Err: test.Err}
+		if kind := err.Kind(); kind != test.Kind {
+			t.Errorf("%v: kind is %v, want %v", test.Err, kind, test.Kind)
+		}
+		if test.Kind != nil && !errors.Is(err, test.Kind) {
+			t.Errorf("%v: errors.Is(err, %v) returned false", test.Err, test.Kind)
+		}
+	}
+}
+
+func TestCollectionErrorLookup(t *testing.T) {
+	proc := winproc.Process{
+		Errors: []winproc.CollectionError{
+			{Collector: winproc.CollectUsers, Op: "user", Err: syscall.Errno(5)},
+		},
+	}
+	if err := proc.Err(winproc.CollectUsers); !errors.Is(err, winproc.ErrAccessDenied) {
+		t.Errorf("users: got %v, want access denied", err)
+	}
+	if err := proc.Err(winproc.CollectTimes); err != nil {
+		t.Errorf("times: got %v, want nil", err)
+	}
+}
+
+func TestNotInJobIgnoresFailures(t *testing.T) {
+	outside := winproc.Process{ID: 100}
+	failed := winproc.Process{ID: 200, Errors: []winproc.CollectionError{
+		{Collector: winproc.CollectJobs, Op: "job membership", Err: syscall.Errno(5)},
+	}}
+
+	filter := winproc.NotInJob()
+	if !filter(outside) {
+		t.Error("a process outside any job was not matched")
+	}
+	if filter(failed) {
+		t.Error("a process with unknown job membership was matched")
+	}
+}
@@ -5,7 +5,6 @@ package winproc
 
 import (
 	"strings"
-	"sync"
 
 	"github.com/gentlemanautomaton/cmdline/cmdlinewindows"
 )
@@ -14,7 +13,8 @@ import (
 // about a process.
 //
 // Information will only be collected for processes that have not been
-// excluded by previous filtering options.
+// excluded by previous filtering options. Failures are recorded in the
+// Errors field of each process.
 type Collector int
 
 const (
@@ -37,8 +37,72 @@ const (
 	// CollectCriticality is an option that enables collection of process
 	// criticality information.
 	CollectCriticality
+
+	// CollectJobs is an option that enables collection of process
+	// job object membership and job memory information.
+	//
+	// The IdentifyJobs option can be used to determine which of a set
+	// of named jobs a process belongs to.
+	CollectJobs
+
+	// CollectConsoleHosts is an option that enables collection of the
+	// console host process that serves each console application.
+	CollectConsoleHosts
+
+	// CollectPackages is an option that enables collection of package
+	// identity information for packaged (AppX or MSIX) applications.
+	CollectPackages
+
+	// CollectFileVersion is an option that enables collection of version
+	// information from the executable image of each process. It also
+	// collects the image path.
+	//
+	// Version information is cached by image path, size and modification
+	// time, so each image file is only read once.
+	CollectFileVersion
+
+	// CollectSignatures is an option that enables inspection of the
+	// Authenticode signature embedded in the executable image of each
+	// process. It also collects the image path.
+	//
+	// Signatures are cached by image path, size and modification time, so
+	// each image file is only examined once.
+	CollectSignatures
+
+	// CollectCycleTime is an option that enables collection of the CPU
+	// clock cycles used by each process. The value is stored in
+	// Times.Cycles.
+	CollectCycleTime
+
+	// CollectHashes is an option that enables collection of the SHA-256
+	// hash of the executable image of each process. It also collects the
+	// image path.
+	//
+	// It shares a single cache across all collections. Use NewImageHasher
+	// to collect other hashes or to configure the cache.
+	CollectHashes
+
+	// CollectServices is an option that enables collection of the windows
+	// services hosted by each process. The local Service Control Manager
+	// is queried once per collection.
+	//
+	// Use ServiceCollector to query a different source.
+	CollectServices
+
+	// CollectWindows is an option that enables collection of the top-level
+	// windows owned by each process. The windows are enumerated once per
+	// collection.
+	//
+	// Only windows on the calling thread's desktop can be enumerated.
+	// Services running in session 0 will not see the windows of
+	// interactive users. Use WindowCollector to query a different source.
+	CollectWindows
 )
 
+// bulkCollectors holds the collectors that examine the whole collection at
+// once, rather than opening each process in turn.
+const bulkCollectors = CollectHashes | CollectServices | CollectWindows
+
 // Contains returns true if c contains b.
 func (c Collector) Contains(b Collector) bool {
 	return c&b == b
@@ -50,66 +114,156 @@ func (c Collector) Apply(col *Collection) {
 		return
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(col.Procs))
+	c.collectBulk(col)
+
+	if perProcess := c &^ bulkCollectors; perProcess != 0 {
+		col.forEach(perProcess, func(proc *Process) {
+			perProcess.collect(col, proc)
+		})
+	}
+}
+
+// collectBulk applies the bulk collectors in c to the collection.
+func (c Collector) collectBulk(col *Collection) {
+	if c.Contains(CollectHashes) {
+		defaultImageHasher.Apply(col)
+	}
+	if c.Contains(CollectServices) {
+		ServiceCollector{}.Apply(col)
+	}
+	if c.Contains(CollectWindows) {
+		WindowCollector{}.Apply(col)
+	}
+}
+
+// collect collects information about proc.
+func (c Collector) collect(col *Collection, proc *Process) {
+	ref, err := col.open(proc.ID)
+	if err != nil {
+		proc.fail(c, "open", err)
+		return
+	}
+	defer ref.Close()
+
+	c.collectRef(ref, proc)
+}
 
-	for i := range col.Procs {
-		if col.Excluded[i] {
-			wg.Done()
-			continue
+// collectRef collects information about proc from ref.
+func (c Collector) collectRef(ref *Ref, proc *Process) {
+	if c.Contains(CollectCommands) {
+		if line, err := ref.CommandLine(); err == nil {
+			proc.CommandLine = strings.TrimSpace(line)
+			proc.Path, proc.Args = cmdlinewindows.SplitCommand(line)
+		} else {
+			proc.fail(CollectCommands, "command line", err)
 		}
-		go func(i int) {
-			defer wg.Done()
-			proc := &col.Procs[i]
+	}
 
-			ref, err := Open(proc.ID)
-			if err != nil {
-				return
-			}
-			defer ref.Close()
+	if c.Contains(CollectSessions) {
+		if sessionID, err := ref.SessionID(); err == nil {
+			proc.SessionID = sessionID
+		} else {
+			proc.fail(CollectSessions, "session", err)
+		}
+	}
 
-			if c.Contains(CollectCommands) {
-				if line, err := ref.CommandLine(); err == nil {
-					proc.CommandLine = strings.TrimSpace(line)
-					proc.Path, proc.Args = cmdlinewindows.SplitCommand(line)
-				}
-			}
+	if c.Contains(CollectUsers) {
+		if user, err := ref.User(); err == nil {
+			proc.User = user
+		} else {
+			proc.fail(CollectUsers, "user", err)
+		}
+	}
 
-			if c.Contains(CollectSessions) {
-				if sessionID, err := ref.SessionID(); err == nil {
-					proc.SessionID = sessionID
-				}
-			}
+	if c.Contains(CollectTimes) {
+		if times, err := ref.Times(); err == nil {
+			proc.Times = times
+		} else {
+			proc.fail(CollectTimes, "times", err)
+		}
+	}
 
-			if c.Contains(CollectUsers) {
-				if user, err := ref.User(); err == nil {
-					proc.User = user
-				}
-			}
+	if c.Contains(CollectCycleTime) {
+		if cycles, err := ref.CycleTime(); err == nil {
+			proc.Times.Cycles = cycles
+		} else {
+			proc.fail(CollectCycleTime, "cycle time", err)
+		}
+	}
 
-			if c.Contains(CollectTimes) {
-				if times, err := ref.Times(); err == nil {
-					proc.Times = times
-				}
-			}
+	if c.Contains(CollectCriticality) {
+		if critical, err := ref.Critical(); err == nil {
+			proc.Critical = critical
+		} else {
+			proc.fail(CollectCriticality, "criticality", err)
+		}
+	}
 
-			if c.Contains(CollectCriticality) {
-				if critical, err := ref.Critical(); err == nil {
-					proc.Critical = critical
-				}
+	if c.Contains(CollectJobs) {
+		if inJob, err := ref.InJob(); err == nil {
+			proc.Job.InJob = inJob
+		} else {
+			proc.fail(CollectJobs, "job membership", err)
+		}
+		if proc.Job.InJob {
+			if memory, err := ref.JobMemory(); err == nil {
+				proc.Job.Memory = memory
+			} else {
+				proc.fail(CollectJobs, "job memory", err)
 			}
-		}(i)
+		}
 	}
 
-	wg.Wait()
+	if c.Contains(CollectConsoleHosts) {
+		if hostID, err := ref.ConsoleHostID(); err == nil {
+			proc.ConsoleHostID = hostID
+		} else {
+			proc.fail(CollectConsoleHosts, "console host", err)
+		}
+	}
+
+	if c.Contains(CollectPackages) {
+		if pkg, err := ref.Package(); err == nil {
+			proc.Package = pkg
+		} else {
+			proc.fail(CollectPackages, "package", err)
+		}
+	}
+
+	if imageCollectors := c & (CollectFileVersion | CollectSignatures); imageCollectors != 0 {
+		if path, err := ref.ImagePath(); err == nil {
+			proc.ImagePath = path
+		} else {
+			proc.fail(imageCollectors, "image path", err)
+		}
+	}
+
+	if c.Contains(CollectFileVersion) && proc.ImagePath != "" {
+		if info, err := fileVersions.Lookup(proc.ImagePath, readFileVersion); err == nil {
+			proc.Version = info
+		} else {
+			proc.fail(CollectFileVersion, "file version", err)
+		}
+	}
+
+	if c.Contains(CollectSignatures) && proc.ImagePath != "" {
+		if signing, err := signatures.Lookup(proc.ImagePath, readSigning); err == nil {
+			proc.Signing = signing
+		} else {
+			proc.fail(CollectSignatures, "signature", err)
+		}
+	}
 }
 
 // Merge attempts to merge the collector with the next option. It returns true
 // if successful.
 func (c Collector) Merge(next CollectionOption) (merged CollectionOption, ok bool) {
-	n, ok := next.(Collector)
-	if !ok {
-		return nil, false
+	switch n := next.(type) {
+	case Collector:
+		return c | n, true
+	case collectorSet:
+		n.builtin |= c
+		return n, true
 	}
-	return c | n, true
+	return nil, false
 }
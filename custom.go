@@ -0,0 +1,96 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import "fmt"
+
+// A CustomCollector collects custom information about a process. It is
+// typically stored in the process with an extension key.
+//
+// Custom collectors are called concurrently for different processes. They
+// are called one at a time for each process, after the built-in collectors.
+type CustomCollector interface {
+	Collect(ref *Ref, proc *Process) error
+}
+
+// CustomCollectorFunc is a function that can be used as a custom collector.
+type CustomCollectorFunc func(ref *Ref, proc *Process) error
+
+// Collect calls fn(ref, proc).
+func (fn CustomCollectorFunc) Collect(ref *Ref, proc *Process) error {
+	return fn(ref, proc)
+}
+
+// Collect returns a collection option that applies the given custom
+// collectors to each process. Errors returned by the collectors are
+// recorded in the Errors field of each process.
+//
+// When it is adjacent to built-in collector options, all of the collectors
+// share a single collection pass in which each process is opened once.
+func Collect(collectors ...CustomCollector) CollectionOption {
+	return collectorSet{custom: collectors}
+}
+
+// collectorSet is a combination of built-in and custom collectors that
+// are applied in a single collection pass.
+type collectorSet struct {
+	builtin Collector
+	custom  []CustomCollector
+}
+
+// Apply applies the collector set to the collection.
+func (set collectorSet) Apply(col *Collection) {
+	if set.builtin == 0 && len(set.custom) == 0 {
+		return
+	}
+
+	set.builtin.collectBulk(col)
+
+	builtin := set.builtin &^ bulkCollectors
+	col.forEach(builtin, func(proc *Process) {
+		ref, err := col.open(proc.ID)
+		if err != nil {
+			proc.fail(builtin, "open", err)
+			return
+		}
+		defer ref.Close()
+
+		builtin.collectRef(ref, proc)
+		collectCustom(set.custom, ref, proc)
+	})
+}
+
+// Merge attempts to merge the collector set with the next option. It
+// returns true if successful.
+func (set collectorSet) Merge(next CollectionOption) (merged CollectionOption, ok bool) {
+	switch n := next.(type) {
+	case Collector:
+		set.builtin |= n
+		return set, true
+	case collectorSet:
+		set.builtin |= n.builtin
+		set.custom = append(set.custom[:len(set.custom):len(set.custom)], n.custom...)
+		return set, true
+	}
+	return nil, false
+}
+
+// collectCustom applies each of the custom collectors to proc, recording
+// any errors that they return.
+func collectCustom(collectors []CustomCollector, ref *Ref, proc *Process) {
+	for _, custom := range collectors {
+		if err := custom.Collect(ref, proc); err != nil {
+			proc.fail(0, customOp(custom), err)
+		}
+	}
+}
+
+// customOp returns the name of the operation performed by a custom
+// collector, for use in collection errors.
+func customOp(custom CustomCollector) string {
+	if s, ok := custom.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return "custom"
+}
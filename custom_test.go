@@ -0,0 +1,76 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+func TestExtensionKey(t *testing.T) {
+	first := winproc.NewExtensionKey[string]("version")
+	second := winproc.NewExtensionKey[string]("version")
+
+	var proc winproc.Process
+	first.Set(&proc, "1.2.3")
+
+	if value, ok := first.Get(proc); !ok || value != "1.2.3" {
+		t.Errorf("first key: got %q (%t), want %q", value, ok, "1.2.3")
+	}
+	if value, ok := second.Get(proc); ok {
+		t.Errorf("second key: got %q, want no value", value)
+	}
+}
+
+func TestCustomCollector(t *testing.T) {
+	key := winproc.NewExtensionKey[bool]("has image path")
+	errFailed := errors.New("custom collection failed")
+
+	var (
+		succeed = winproc.CustomCollectorFunc(func(ref *winproc.Ref, proc *winproc.Process) error {
+			path, err := ref.ImagePath()
+			if err != nil {
+				return err
+			}
+			key.Set(proc, path != "")
+			return nil
+		})
+		fail = winproc.CustomCollectorFunc(func(ref *winproc.Ref, proc *winproc.Process) error {
+			return errFailed
+		})
+	)
+
+	procs, err := winproc.List(
+		winproc.Include(winproc.MatchID(winproc.ID(os.Getpid()))),
+		winproc.CollectTimes,
+		winproc.Collect(succeed, fail),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 1 {
+		t.Fatalf("expected 1 process, found %d", len(procs))
+	}
+	proc := procs[0]
+
+	if proc.Times.Creation.IsZero() {
+		t.Errorf("built-in collector did not collect times")
+	}
+	if value, ok := key.Get(proc); !ok || !value {
+		t.Errorf("custom collector did not store its value")
+	}
+
+	found := false
+	for _, err := range proc.Errors {
+		if errors.Is(err, errFailed) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("custom collector error was not recorded")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"sync"
+
+	"github.com/gentlemanautomaton/winproc/processaccess"
+	"github.com/gentlemanautomaton/winproc/token"
+)
+
+// debugPrivilege keeps SeDebugPrivilege enabled in the process token while
+// any calls to OpenDebug need it. It is shared because privileges are
+// enabled for the process as a whole, and concurrent callers would
+// otherwise restore them out from under each other.
+var debugPrivilege struct {
+	mutex   sync.Mutex
+	users   int
+	restore token.RestoreFunc
+}
+
+// acquireDebugPrivilege enables SeDebugPrivilege in the process token if
+// it isn't already enabled by another caller. Each successful call must be
+// followed by a call to releaseDebugPrivilege.
+func acquireDebugPrivilege() error {
+	debugPrivilege.mutex.Lock()
+	defer debugPrivilege.mutex.Unlock()
+
+	if debugPrivilege.users == 0 {
+		restore, err := token.Enable(token.Debug)
+		if err != nil {
+			return err
+		}
+		debugPrivilege.restore = restore
+	}
+	debugPrivilege.users++
+	return nil
+}
+
+// releaseDebugPrivilege restores SeDebugPrivilege to its original state
+// once it is no longer needed by any caller.
+func releaseDebugPrivilege() {
+	debugPrivilege.mutex.Lock()
+	defer debugPrivilege.mutex.Unlock()
+
+	debugPrivilege.users--
+	if debugPrivilege.users == 0 {
+		debugPrivilege.restore()
+		debugPrivilege.restore = nil
+	}
+}
+
+// OpenDebug returns a reference to the process with the given ID and
+// access rights, like Open. If access is denied it enables SeDebugPrivilege
+// for the current process and tries again, which permits access to
+// processes belonging to other users and services.
+//
+// The privilege is only held by elevated administrators. If it can't be
+// enabled the original error is returned. The privilege is restored to its
+// original state before OpenDebug returns.
+func OpenDebug(pid ID, rights ...processaccess.Rights) (*Ref, error) {
+	ref, err := Open(pid, rights...)
+	if err == nil || errorKind(err) != ErrAccessDenied {
+		return ref, err
+	}
+
+	if acquireDebugPrivilege() != nil {
+		return nil, err
+	}
+	defer releaseDebugPrivilege()
+
+	return Open(pid, rights...)
+}
+
+// DebugPrivilege is a collection option that permits collectors to enable
+// SeDebugPrivilege when they are denied access to a process. See OpenDebug
+// for details.
+//
+// It only affects collectors that follow it.
+type DebugPrivilege bool
+
+// Apply applies the debug privilege setting to the collection.
+func (d DebugPrivilege) Apply(col *Collection) {
+	col.Debug = bool(d)
+}
+
+// open returns a reference to the process with the given ID and the
+// default access rights. If the collection permits it, SeDebugPrivilege is
+// used when access is denied.
+func (col *Collection) open(pid ID) (*Ref, error) {
+	if col.Debug {
+		return OpenDebug(pid)
+	}
+	return Open(pid)
+}
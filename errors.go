@@ -13,4 +13,24 @@ var (
 	// ErrProcessStillActive is returned when a process is still active and
 	// has not exited yet.
 	ErrProcessStillActive = errors.New("the process is still active")
+
+	// ErrProcessReplaced is returned when a process has exited and its
+	// process ID has been reused by another process.
+	ErrProcessReplaced = errors.New("the process has been replaced by another process with the same ID")
+
+	// ErrAccessDenied is matched by collection errors that were caused by
+	// insufficient access rights.
+	ErrAccessDenied = errors.New("access to the process was denied")
+
+	// ErrProcessGone is matched by collection errors that were caused by
+	// a process exiting before its information could be collected.
+	ErrProcessGone = errors.New("the process has exited")
+
+	// ErrUnsupported is matched by collection errors that were caused by
+	// an operation that isn't supported by the operating system.
+	ErrUnsupported = errors.New("the operation is not supported by this version of windows")
+
+	// ErrTimeout is matched by collection errors that were caused by an
+	// operation that did not complete in time.
+	ErrTimeout = errors.New("the operation timed out")
 )
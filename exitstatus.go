@@ -0,0 +1,111 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/ntstatus"
+	"golang.org/x/sys/windows"
+)
+
+// ExitStatus describes how a process exited.
+type ExitStatus struct {
+	Code  uint32 // The exit code of the process
+	Times Times  // The creation and exit times and final CPU times
+}
+
+// Status returns the exit code as an NTSTATUS value.
+func (s ExitStatus) Status() ntstatus.Value {
+	return ntstatus.Value(s.Code)
+}
+
+// Crashed returns true if the exit code is one of the NTSTATUS exception
+// or fail fast codes reported when a process is terminated by an unhandled
+// exception, such as an access violation or a breakpoint. The loader
+// failures reported when a process can't start, such as a missing DLL or
+// an invalid image, are also considered crashes.
+//
+// Other exit codes are not considered crashes, even if they happen to have
+// error severity. A process that calls exit(-1), for example, exits with
+// code 0xFFFFFFFF, and a process that exits in response to CTRL+C or
+// CTRL+BREAK exits with STATUS_CONTROL_C_EXIT.
+func (s ExitStatus) Crashed() bool {
+	_, ok := crashReasons[s.Status()]
+	return ok
+}
+
+// Reason returns a description of the reason that the process crashed,
+// such as "access violation". It returns an empty string if the process
+// did not crash.
+func (s ExitStatus) Reason() string {
+	return crashReasons[s.Status()]
+}
+
+// crashReasons maps the NTSTATUS values that are reported when a process
+// crashes or fails to load to descriptions of their causes.
+var crashReasons = map[ntstatus.Value]string{
+	ntstatus.Breakpoint:            "breakpoint",
+	ntstatus.AccessViolation:       "access violation",
+	ntstatus.InPageError:           "in-page error",
+	ntstatus.NoMemory:              "out of memory",
+	ntstatus.IllegalInstruction:    "illegal instruction",
+	ntstatus.InvalidImageFormat:    "invalid image format",
+	ntstatus.ArrayBoundsExceeded:   "array bounds exceeded",
+	ntstatus.FloatDivideByZero:     "floating point divide by zero",
+	ntstatus.IntegerDivideByZero:   "integer divide by zero",
+	ntstatus.IntegerOverflow:       "integer overflow",
+	ntstatus.PrivilegedInstruction: "privileged instruction",
+	ntstatus.StackOverflow:         "stack overflow",
+	ntstatus.DLLNotFound:           "dll not found",
+	ntstatus.EntryPointNotFound:    "entry point not found",
+	ntstatus.DLLInitFailed:         "dll initialization failed",
+	ntstatus.HeapCorruption:        "heap corruption",
+	ntstatus.StackBufferOverrun:    "stack buffer overrun or fail fast",
+	ntstatus.AssertionFailure:      "assertion failure",
+}
+
+// ExitStatus returns the exit status of the process if it has exited.
+//
+// Unlike ExitCode, it examines the state of the process before retrieving
+// its exit code, so that an exit code of 259 is reported correctly. If the
+// process is still running it returns ErrProcessStillActive.
+//
+// The reference must have been opened with the QueryLimitedInformation
+// and Synchronize access rights.
+func (ref *Ref) ExitStatus() (ExitStatus, error) {
+	exited, err := ref.exited()
+	if err != nil {
+		return ExitStatus{}, err
+	}
+	if !exited {
+		return ExitStatus{}, ErrProcessStillActive
+	}
+
+	code, err := ref.rawExitCode()
+	if err != nil {
+		return ExitStatus{}, err
+	}
+
+	times, err := ref.Times()
+	if err != nil {
+		return ExitStatus{}, err
+	}
+
+	return ExitStatus{Code: code, Times: times}, nil
+}
+
+// rawExitCode returns the exit code reported for the process without
+// interpreting it.
+func (ref *Ref) rawExitCode() (code uint32, err error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return 0, ErrClosed
+	}
+
+	err = windows.GetExitCodeProcess(windows.Handle(ref.handle), &code)
+	return
+}
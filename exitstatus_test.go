@@ -0,0 +1,69 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+func TestExitStatusReason(t *testing.T) {
+	tests := []struct {
+		Code    uint32
+		Crashed bool
+		Reason  string
+	}{
+		{0, false, ""},
+		{1, false, ""},
+		{259, false, ""},
+		{0xC0000005, true, "access violation"},
+		{0xC0000409, true, "stack buffer overrun or fail fast"},
+		{0x80000003, true, "breakpoint"},
+		{0xC0001234, false, ""},
+		{0xFFFFFFFF, false, ""},
+		{0xC000013A, false, ""},
+	}
+	for _, test := range tests {
+		status := winproc.ExitStatus{Code: test.Code}
+		if crashed := status.Crashed(); crashed != test.Crashed {
+			t.Errorf("%#x: crashed is %t, want %t", test.Code, crashed, test.Crashed)
+		}
+		if reason := status.Reason(); reason != test.Reason {
+			t.Errorf("%#x: reason is %q, want %q", test.Code, reason, test.Reason)
+		}
+	}
+}
+
+func TestExitStatusStillActiveCode(t *testing.T) {
+	command := exec.Command("cmd", "/c", "exit 259")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := winproc.FromCmd(command, processaccess.QueryLimitedInformation, processaccess.Synchronize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if err := ref.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	command.Wait()
+
+	status, err := ref.ExitStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Code != 259 {
+		t.Errorf("expected exit code 259, got %d", status.Code)
+	}
+	if status.Times.Exit.IsZero() {
+		t.Errorf("exit time was not reported")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+// Extensions hold custom information about a process that has been
+// gathered by custom collectors. Values are stored and retrieved with
+// extension keys.
+type Extensions map[any]any
+
+// ExtensionKey identifies a value of type T within the extensions of a
+// process. Each key is distinct from all others, even those with the same
+// name.
+type ExtensionKey[T any] struct {
+	name string
+}
+
+// NewExtensionKey returns a new extension key for values of type T. The
+// name is used for display purposes only.
+func NewExtensionKey[T any](name string) *ExtensionKey[T] {
+	return &ExtensionKey[T]{name: name}
+}
+
+// Get returns the value of k for p. It returns false if the value has not
+// been set.
+func (k *ExtensionKey[T]) Get(p Process) (value T, ok bool) {
+	value, ok = p.Extensions[k].(T)
+	return
+}
+
+// Set stores the value of k for p.
+func (k *ExtensionKey[T]) Set(p *Process, value T) {
+	if p.Extensions == nil {
+		p.Extensions = make(Extensions)
+	}
+	p.Extensions[k] = value
+}
+
+// String returns the name of the key.
+func (k *ExtensionKey[T]) String() string {
+	return k.name
+}
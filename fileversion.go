@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import "github.com/gentlemanautomaton/winproc/fileversion"
+
+// fileVersions is shared by all collections so that each image file is only
+// parsed once.
+var fileVersions = newImageCache[fileversion.Info](4096)
+
+// readFileVersion returns the version information of the image file at
+// path. Images without version information produce a zero value.
+func readFileVersion(path string) (fileversion.Info, error) {
+	info, err := fileversion.Read(path)
+	if err == fileversion.ErrNoVersionInfo {
+		return fileversion.Info{}, nil
+	}
+	return info, err
+}
@@ -0,0 +1,6 @@
+// Package fileversion reads version information from the VS_VERSIONINFO
+// resource of portable executable (PE) files.
+//
+// It is implemented in pure Go and does not depend on the windows version
+// APIs, so it can be used on any platform.
+package fileversion
@@ -0,0 +1,13 @@
+package fileversion
+
+import "errors"
+
+var (
+	// ErrNoVersionInfo is returned when a file does not contain a version
+	// resource.
+	ErrNoVersionInfo = errors.New("the file does not contain version information")
+
+	// ErrMalformed is returned when a version resource or the resource
+	// directory that contains it is malformed.
+	ErrMalformed = errors.New("the version information is malformed")
+)
@@ -0,0 +1,263 @@
+package fileversion_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/gentlemanautomaton/winproc/fileversion"
+)
+
+// block describes a VS_VERSIONINFO block for encoding.
+type block struct {
+	Key      string
+	Text     string // Used for text values
+	Binary   []byte // Used for binary values
+	Children []block
+}
+
+// encode returns the encoded form of b, which must begin on a 32-bit
+// boundary.
+func (b block) encode() []byte {
+	var buf bytes.Buffer
+	put16 := func(v uint16) { binary.Write(&buf, binary.LittleEndian, v) }
+	putText := func(s string) {
+		for _, c := range utf16.Encode([]rune(s)) {
+			put16(c)
+		}
+		put16(0)
+	}
+	pad := func() {
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+
+	var valueLength, valueType uint16
+	switch {
+	case b.Text != "":
+		valueLength, valueType = uint16(len(utf16.Encode([]rune(b.Text)))+1), 1
+	case b.Binary != nil:
+		valueLength = uint16(len(b.Binary))
+	default:
+		valueType = 1
+	}
+
+	put16(0) // Length, filled in below
+	put16(valueLength)
+	put16(valueType)
+	putText(b.Key)
+	pad()
+	switch {
+	case b.Text != "":
+		putText(b.Text)
+		pad()
+	case b.Binary != nil:
+		buf.Write(b.Binary)
+		pad()
+	}
+	for i, child := range b.Children {
+		data := child.encode()
+		if i == len(b.Children)-1 {
+			// The length of the last child excludes its padding
+			buf.Write(data)
+			break
+		}
+		buf.Write(data)
+		pad()
+	}
+
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint16(data, uint16(len(data)))
+	return data
+}
+
+func fixedFileInfo(fileMS, fileLS, productMS, productLS uint32) []byte {
+	values := []uint32{0xFEEF04BD, 0x00010000, fileMS, fileLS, productMS, productLS, 0x3F, 0, 0x40004, 1, 0, 0, 0}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, values)
+	return buf.Bytes()
+}
+
+func sampleResource() []byte {
+	return block{
+		Key:    "VS_VERSION_INFO",
+		Binary: fixedFileInfo(0x000A0000, 0x4A610001, 0x000A0000, 0x4A610000),
+		Children: []block{
+			{Key: "StringFileInfo", Children: []block{
+				{Key: "040704B0", Children: []block{
+					{Key: "CompanyName", Text: "Falsche Firma"},
+				}},
+				{Key: "040904B0", Children: []block{
+					{Key: "CompanyName", Text: "Gentleman Automaton"},
+					{Key: "FileDescription", Text: "Sample Tool"},
+					{Key: "FileVersion", Text: "10.0.19041.1"},
+					{Key: "OriginalFilename", Text: "sample.exe"},
+					{Key: "ProductVersion", Text: "10.0.19041.0"},
+				}},
+			}},
+			{Key: "VarFileInfo", Children: []block{
+				{Key: "Translation", Binary: []byte{0x09, 0x04, 0xB0, 0x04}},
+			}},
+		},
+	}.encode()
+}
+
+// samplePE returns a minimal 64-bit portable executable with a single
+// resource section that holds the given RT_VERSION resource.
+func samplePE(resource []byte) []byte {
+	const (
+		fileAlign    = 0x200
+		sectionRVA   = 0x1000
+		headerOffset = 0x40
+	)
+
+	// Build the resource section: type, name and language directories
+	// followed by the data entry and the data itself
+	var rsrc bytes.Buffer
+	le := func(v interface{}) { binary.Write(&rsrc, binary.LittleEndian, v) }
+	directory := func(id, offset uint32) {
+		le([3]uint32{})           // Characteristics, TimeDateStamp, Version
+		le([2]uint16{0, 1})       // NumberOfNamedEntries, NumberOfIdEntries
+		le([2]uint32{id, offset}) // Entry
+	}
+	directory(16, 0x80000000|24) // RT_VERSION -> name directory
+	directory(1, 0x80000000|48)  // VS_VERSION_INFO -> language directory
+	directory(0x0409, 72)        // en-US -> data entry
+	dataRVA := uint32(sectionRVA + 72 + 16)
+	le([4]uint32{dataRVA, uint32(len(resource)), 0, 0})
+	rsrc.Write(resource)
+	for rsrc.Len()%fileAlign != 0 {
+		rsrc.WriteByte(0)
+	}
+
+	var f bytes.Buffer
+	w := func(v interface{}) { binary.Write(&f, binary.LittleEndian, v) }
+
+	// DOS header
+	dos := make([]byte, headerOffset)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3C:], headerOffset)
+	f.Write(dos)
+
+	// PE signature and COFF header
+	f.WriteString("PE\x00\x00")
+	const optionalHeaderSize = 112 + 16*8
+	w(uint16(0x8664))             // Machine
+	w(uint16(1))                  // NumberOfSections
+	w([3]uint32{})                // TimeDateStamp, PointerToSymbolTable, NumberOfSymbols
+	w(uint16(optionalHeaderSize)) // SizeOfOptionalHeader
+	w(uint16(0x0022))             // Characteristics
+
+	// Optional header
+	headersSize := uint32(fileAlign)
+	w(uint16(0x20B))                                 // Magic
+	w([2]uint8{})                                    // Linker version
+	w([3]uint32{0, uint32(rsrc.Len()), 0})           // Code and data sizes
+	w([2]uint32{0, 0})                               // AddressOfEntryPoint, BaseOfCode
+	w(uint64(0x140000000))                           // ImageBase
+	w([2]uint32{sectionRVA, fileAlign})              // SectionAlignment, FileAlignment
+	w([6]uint16{6, 0, 0, 0, 6, 0})                   // OS, image and subsystem versions
+	w(uint32(0))                                     // Win32VersionValue
+	w(uint32(sectionRVA + sectionRVA))               // SizeOfImage
+	w(headersSize)                                   // SizeOfHeaders
+	w(uint32(0))                                     // CheckSum
+	w([2]uint16{3, 0})                               // Subsystem, DllCharacteristics
+	w([4]uint64{0x100000, 0x1000, 0x100000, 0x1000}) // Stack and heap sizes
+	w([2]uint32{0, 16})                              // LoaderFlags, NumberOfRvaAndSizes
+	for i := 0; i < 16; i++ {
+		if i == 2 {
+			w([2]uint32{sectionRVA, uint32(rsrc.Len())})
+		} else {
+			w([2]uint32{})
+		}
+	}
+
+	// Section header
+	var name [8]byte
+	copy(name[:], ".rsrc")
+	w(name)
+	w([4]uint32{uint32(rsrc.Len()), sectionRVA, uint32(rsrc.Len()), headersSize})
+	w([2]uint32{})        // Relocations and line numbers
+	w([2]uint16{})        // Relocation and line number counts
+	w(uint32(0x40000040)) // Characteristics
+
+	for f.Len() < int(headersSize) {
+		f.WriteByte(0)
+	}
+	f.Write(rsrc.Bytes())
+
+	return f.Bytes()
+}
+
+func checkInfo(t *testing.T, info fileversion.Info) {
+	t.Helper()
+
+	if got, want := info.FileVersion.String(), "10.0.19041.1"; got != want {
+		t.Errorf("FileVersion: got %s, want %s", got, want)
+	}
+	if got, want := info.ProductVersion.String(), "10.0.19041.0"; got != want {
+		t.Errorf("ProductVersion: got %s, want %s", got, want)
+	}
+	if got, want := info.CompanyName, "Gentleman Automaton"; got != want {
+		t.Errorf("CompanyName: got %q, want %q", got, want)
+	}
+	if got, want := info.FileDescription, "Sample Tool"; got != want {
+		t.Errorf("FileDescription: got %q, want %q", got, want)
+	}
+	if got, want := info.OriginalFilename, "sample.exe"; got != want {
+		t.Errorf("OriginalFilename: got %q, want %q", got, want)
+	}
+	if got, want := info.FileVersionText, "10.0.19041.1"; got != want {
+		t.Errorf("FileVersionText: got %q, want %q", got, want)
+	}
+}
+
+func TestParseResource(t *testing.T) {
+	info, err := fileversion.ParseResource(sampleResource())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInfo(t, info)
+}
+
+func TestParseResourceMalformed(t *testing.T) {
+	data := sampleResource()
+	for _, size := range []int{0, 4, 10, 40, len(data) / 2} {
+		if _, err := fileversion.ParseResource(data[:size]); !errors.Is(err, fileversion.ErrMalformed) {
+			t.Errorf("size %d: expected ErrMalformed, got %v", size, err)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sample.exe")
+	if err := os.WriteFile(path, samplePE(sampleResource()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := fileversion.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInfo(t, info)
+}
+
+func TestParseNoVersionInfo(t *testing.T) {
+	// The Go toolchain ships with PE files that lack version resources
+	path := filepath.Join(runtime.GOROOT(), "src", "debug", "pe", "testdata", "gcc-amd64-mingw-exec")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+
+	if _, err := fileversion.Parse(f); err != fileversion.ErrNoVersionInfo {
+		t.Errorf("expected ErrNoVersionInfo, got %v", err)
+	}
+}
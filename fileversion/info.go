@@ -0,0 +1,29 @@
+package fileversion
+
+// Info holds version information for a file.
+//
+// The FileVersion and ProductVersion fields are taken from the fixed file
+// information. The string fields are taken from the preferred string table,
+// which may describe the versions differently.
+type Info struct {
+	FileVersion    Version
+	ProductVersion Version
+
+	CompanyName        string
+	FileDescription    string
+	FileVersionText    string // The FileVersion string
+	ProductName        string
+	ProductVersionText string // The ProductVersion string
+	OriginalFilename   string
+	InternalName       string
+	LegalCopyright     string
+
+	// Strings holds every value in the preferred string table, keyed by
+	// name.
+	Strings map[string]string
+}
+
+// IsZero returns true if info does not contain any version information.
+func (info Info) IsZero() bool {
+	return info.FileVersion.IsZero() && info.ProductVersion.IsZero() && len(info.Strings) == 0
+}
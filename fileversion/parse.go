@@ -0,0 +1,185 @@
+package fileversion
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	fixedFileInfoSignature = 0xFEEF04BD
+	fixedFileInfoSize      = 52 // sizeof(VS_FIXEDFILEINFO)
+	nodeHeaderSize         = 6  // wLength, wValueLength and wType
+	textType               = 1  // wType for text values
+)
+
+// node is a single block of a VS_VERSIONINFO resource. Every block shares
+// the same layout: a header, a key, a value and a set of child blocks.
+//
+// https://docs.microsoft.com/en-us/windows/win32/menurc/vs-versioninfo
+type node struct {
+	Key      string
+	Text     bool
+	Value    []byte
+	Children []node
+}
+
+// ParseResource parses the contents of a VS_VERSIONINFO resource.
+func ParseResource(data []byte) (Info, error) {
+	root, _, err := parseNode(data, 0)
+	if err != nil {
+		return Info{}, err
+	}
+	if root.Key != "VS_VERSION_INFO" {
+		return Info{}, fmt.Errorf("%w: unexpected root key %q", ErrMalformed, root.Key)
+	}
+
+	var info Info
+
+	if len(root.Value) >= fixedFileInfoSize {
+		v := root.Value
+		if binary.LittleEndian.Uint32(v[0:]) != fixedFileInfoSignature {
+			return Info{}, fmt.Errorf("%w: invalid fixed file info signature", ErrMalformed)
+		}
+		info.FileVersion = versionFromParts(binary.LittleEndian.Uint32(v[8:]), binary.LittleEndian.Uint32(v[12:]))
+		info.ProductVersion = versionFromParts(binary.LittleEndian.Uint32(v[16:]), binary.LittleEndian.Uint32(v[20:]))
+	}
+
+	if table, ok := preferredTable(root); ok {
+		info.Strings = make(map[string]string, len(table.Children))
+		for _, child := range table.Children {
+			info.Strings[child.Key] = decodeText(child.Value)
+		}
+		info.CompanyName = info.Strings["CompanyName"]
+		info.FileDescription = info.Strings["FileDescription"]
+		info.FileVersionText = info.Strings["FileVersion"]
+		info.ProductName = info.Strings["ProductName"]
+		info.ProductVersionText = info.Strings["ProductVersion"]
+		info.OriginalFilename = info.Strings["OriginalFilename"]
+		info.InternalName = info.Strings["InternalName"]
+		info.LegalCopyright = info.Strings["LegalCopyright"]
+	}
+
+	return info, nil
+}
+
+// preferredTable returns the string table that best describes the file.
+//
+// It prefers the first language listed in the translation table, followed
+// by US English and then the first table present.
+func preferredTable(root node) (table node, ok bool) {
+	var tables, vars []node
+	for _, child := range root.Children {
+		switch child.Key {
+		case "StringFileInfo":
+			tables = append(tables, child.Children...)
+		case "VarFileInfo":
+			vars = append(vars, child.Children...)
+		}
+	}
+	if len(tables) == 0 {
+		return node{}, false
+	}
+
+	var preferred []string
+	for _, v := range vars {
+		if v.Key == "Translation" && len(v.Value) >= 4 {
+			lang := binary.LittleEndian.Uint16(v.Value[0:])
+			codepage := binary.LittleEndian.Uint16(v.Value[2:])
+			preferred = append(preferred, fmt.Sprintf("%04x%04x", lang, codepage))
+		}
+	}
+	preferred = append(preferred, "040904b0", "040904e4")
+
+	for _, key := range preferred {
+		for _, table := range tables {
+			if strings.EqualFold(table.Key, key) {
+				return table, true
+			}
+		}
+	}
+	return tables[0], true
+}
+
+// parseNode parses the block that starts at offset within data. Offsets
+// are used for alignment, so data must begin at the start of the resource.
+//
+// It returns the block and the offset of the byte that follows it.
+func parseNode(data []byte, offset int) (n node, end int, err error) {
+	if offset+nodeHeaderSize > len(data) {
+		return node{}, 0, fmt.Errorf("%w: truncated block header", ErrMalformed)
+	}
+	length := int(binary.LittleEndian.Uint16(data[offset:]))
+	valueLength := int(binary.LittleEndian.Uint16(data[offset+2:]))
+	n.Text = binary.LittleEndian.Uint16(data[offset+4:]) == textType
+
+	end = offset + length
+	if length < nodeHeaderSize || end > len(data) {
+		return node{}, 0, fmt.Errorf("%w: invalid block length", ErrMalformed)
+	}
+
+	// Read the null-terminated key
+	pos := offset + nodeHeaderSize
+	var key []uint16
+	for {
+		if pos+2 > end {
+			return node{}, 0, fmt.Errorf("%w: unterminated block key", ErrMalformed)
+		}
+		c := binary.LittleEndian.Uint16(data[pos:])
+		pos += 2
+		if c == 0 {
+			break
+		}
+		key = append(key, c)
+	}
+	n.Key = string(utf16.Decode(key))
+	pos = align(pos)
+
+	// Read the value. Text values are measured in characters.
+	if valueLength > 0 && pos < end {
+		size := valueLength
+		if n.Text {
+			size *= 2
+		}
+		if pos+size > end {
+			size = end - pos
+		}
+		n.Value = data[pos : pos+size]
+		pos = align(pos + size)
+	}
+
+	// Read the children. Some resources have trailing padding that is too
+	// short to hold a block.
+	for pos+nodeHeaderSize <= end {
+		if binary.LittleEndian.Uint16(data[pos:]) == 0 {
+			break
+		}
+		child, next, err := parseNode(data[:end], pos)
+		if err != nil {
+			return node{}, 0, err
+		}
+		n.Children = append(n.Children, child)
+		pos = align(next)
+	}
+
+	return n, end, nil
+}
+
+// align rounds offset up to the next 32-bit boundary.
+func align(offset int) int {
+	return (offset + 3) &^ 3
+}
+
+// decodeText decodes a null-terminated utf16 value.
+func decodeText(value []byte) string {
+	chars := make([]uint16, 0, len(value)/2)
+	for i := 0; i+1 < len(value); i += 2 {
+		c := binary.LittleEndian.Uint16(value[i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+	return string(utf16.Decode(chars))
+}
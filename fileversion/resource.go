@@ -0,0 +1,182 @@
+package fileversion
+
+import (
+	"debug/pe"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+const (
+	resourceDirectoryIndex = 2  // IMAGE_DIRECTORY_ENTRY_RESOURCE
+	resourceTypeVersion    = 16 // RT_VERSION
+
+	resourceDirectorySize = 16 // sizeof(IMAGE_RESOURCE_DIRECTORY)
+	resourceEntrySize     = 8  // sizeof(IMAGE_RESOURCE_DIRECTORY_ENTRY)
+	resourceDataEntrySize = 16 // sizeof(IMAGE_RESOURCE_DATA_ENTRY)
+
+	resourceSubdirectoryFlag = 0x80000000
+	resourceNameFlag         = 0x80000000
+)
+
+// Read returns the version information of the portable executable file at
+// path.
+//
+// It returns ErrNoVersionInfo if the file does not contain a version
+// resource.
+func Read(path string) (Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse returns the version information of the portable executable file
+// provided by r.
+//
+// It returns ErrNoVersionInfo if the file does not contain a version
+// resource.
+func Parse(r io.ReaderAt) (Info, error) {
+	data, err := versionResource(r)
+	if err != nil {
+		return Info{}, err
+	}
+	return ParseResource(data)
+}
+
+// versionResource returns the contents of the first RT_VERSION resource in
+// the portable executable provided by r.
+func versionResource(r io.ReaderAt) ([]byte, error) {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var dir pe.DataDirectory
+	switch header := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		if header.NumberOfRvaAndSizes <= resourceDirectoryIndex {
+			return nil, ErrNoVersionInfo
+		}
+		dir = header.DataDirectory[resourceDirectoryIndex]
+	case *pe.OptionalHeader64:
+		if header.NumberOfRvaAndSizes <= resourceDirectoryIndex {
+			return nil, ErrNoVersionInfo
+		}
+		dir = header.DataDirectory[resourceDirectoryIndex]
+	default:
+		return nil, ErrNoVersionInfo
+	}
+	if dir.VirtualAddress == 0 || dir.Size == 0 {
+		return nil, ErrNoVersionInfo
+	}
+
+	rsrc, err := readVirtual(f, dir.VirtualAddress, dir.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the type, name and language levels of the resource tree,
+	// taking the first entry at the name and language levels
+	offset, err := findEntry(rsrc, 0, resourceTypeVersion)
+	if err != nil {
+		return nil, err
+	}
+	for level := 0; level < 2; level++ {
+		if offset&resourceSubdirectoryFlag == 0 {
+			break // A leaf was found early
+		}
+		if offset, err = firstEntry(rsrc, offset&^resourceSubdirectoryFlag); err != nil {
+			return nil, err
+		}
+	}
+	if offset&resourceSubdirectoryFlag != 0 {
+		return nil, ErrMalformed
+	}
+
+	// Read the data entry, which refers to the resource by its RVA
+	if int(offset)+resourceDataEntrySize > len(rsrc) {
+		return nil, ErrMalformed
+	}
+	rva := binary.LittleEndian.Uint32(rsrc[offset:])
+	size := binary.LittleEndian.Uint32(rsrc[offset+4:])
+
+	return readVirtual(f, rva, size)
+}
+
+// findEntry returns the offset stored in the entry with the given integer
+// ID within the resource directory at dirOffset.
+func findEntry(rsrc []byte, dirOffset uint32, id uint32) (uint32, error) {
+	entries, err := directoryEntries(rsrc, dirOffset)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(entries); i += resourceEntrySize {
+		name := binary.LittleEndian.Uint32(entries[i:])
+		if name&resourceNameFlag == 0 && name == id {
+			return binary.LittleEndian.Uint32(entries[i+4:]), nil
+		}
+	}
+	return 0, ErrNoVersionInfo
+}
+
+// firstEntry returns the offset stored in the first entry of the resource
+// directory at dirOffset.
+func firstEntry(rsrc []byte, dirOffset uint32) (uint32, error) {
+	entries, err := directoryEntries(rsrc, dirOffset)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, ErrNoVersionInfo
+	}
+	return binary.LittleEndian.Uint32(entries[4:]), nil
+}
+
+// directoryEntries returns the entries of the resource directory at
+// dirOffset.
+func directoryEntries(rsrc []byte, dirOffset uint32) ([]byte, error) {
+	start := int(dirOffset)
+	if start+resourceDirectorySize > len(rsrc) {
+		return nil, ErrMalformed
+	}
+	named := int(binary.LittleEndian.Uint16(rsrc[start+12:]))
+	ids := int(binary.LittleEndian.Uint16(rsrc[start+14:]))
+
+	start += resourceDirectorySize
+	end := start + (named+ids)*resourceEntrySize
+	if end > len(rsrc) {
+		return nil, ErrMalformed
+	}
+	return rsrc[start:end], nil
+}
+
+// readVirtual reads size bytes from f at the given relative virtual
+// address.
+func readVirtual(f *pe.File, rva, size uint32) ([]byte, error) {
+	for _, section := range f.Sections {
+		start := section.VirtualAddress
+		length := section.VirtualSize
+		if length == 0 {
+			length = section.Size
+		}
+		if rva < start || rva-start >= length {
+			continue
+		}
+
+		offset := rva - start
+		if uint64(offset)+uint64(size) > uint64(section.Size) {
+			return nil, ErrMalformed
+		}
+		data := make([]byte, size)
+		if _, err := section.ReadAt(data, int64(offset)); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	return nil, ErrMalformed
+}
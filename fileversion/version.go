@@ -0,0 +1,35 @@
+package fileversion
+
+import "strconv"
+
+// Version is a four part version number.
+type Version struct {
+	Major    uint16
+	Minor    uint16
+	Build    uint16
+	Revision uint16
+}
+
+// versionFromParts returns a version from the most and least significant
+// 32 bits of a version number.
+func versionFromParts(ms, ls uint32) Version {
+	return Version{
+		Major:    uint16(ms >> 16),
+		Minor:    uint16(ms),
+		Build:    uint16(ls >> 16),
+		Revision: uint16(ls),
+	}
+}
+
+// IsZero returns true if v is 0.0.0.0.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// String returns a string representation of the version.
+func (v Version) String() string {
+	return strconv.Itoa(int(v.Major)) + "." +
+		strconv.Itoa(int(v.Minor)) + "." +
+		strconv.Itoa(int(v.Build)) + "." +
+		strconv.Itoa(int(v.Revision))
+}
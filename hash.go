@@ -0,0 +1,230 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"sync"
+)
+
+// HashAlgorithm is a set of hash algorithms used to hash process images.
+type HashAlgorithm int
+
+// Hash algorithms.
+const (
+	HashSHA256 HashAlgorithm = 1 << iota
+	HashSHA1
+	HashMD5
+)
+
+// Contains returns true if a contains b.
+func (a HashAlgorithm) Contains(b HashAlgorithm) bool {
+	return a&b == b
+}
+
+// ImageHashes holds hex-encoded hashes of a process image. Only the hashes
+// requested from the ImageHasher are populated.
+type ImageHashes struct {
+	SHA256 string
+	SHA1   string
+	MD5    string
+}
+
+// ErrImageTooLarge is returned when an image file exceeds the maximum size
+// that an ImageHasher is willing to hash.
+var ErrImageTooLarge = errors.New("the image file is too large to hash")
+
+// ImageHasher is a collection option that hashes the executable image of
+// each process. Failures are recorded in the Errors field of each process
+// and attributed to CollectHashes.
+//
+// Hashes are cached by image path, size and modification time, so each
+// image file is only read once. An ImageHasher should be reused across
+// calls to List and Watch so that its cache is shared.
+//
+// It is safe for concurrent use.
+type ImageHasher struct {
+	algorithms  HashAlgorithm
+	workers     int
+	maxFileSize int64
+	cache       *imageCache[ImageHashes]
+}
+
+// ImageHasherOption is an option for an ImageHasher.
+type ImageHasherOption func(*ImageHasher)
+
+// HashWorkers limits the number of image files that an ImageHasher will
+// read at the same time. The default is 2.
+func HashWorkers(n int) ImageHasherOption {
+	return func(h *ImageHasher) {
+		if n > 0 {
+			h.workers = n
+		}
+	}
+}
+
+// HashCacheSize limits the number of images that an ImageHasher will
+// remember. The default is 4096.
+func HashCacheSize(n int) ImageHasherOption {
+	return func(h *ImageHasher) {
+		if n > 0 {
+			h.cache = newImageCache[ImageHashes](n)
+		}
+	}
+}
+
+// HashMaxFileSize prevents an ImageHasher from hashing image files larger
+// than n bytes. By default there is no limit.
+func HashMaxFileSize(n int64) ImageHasherOption {
+	return func(h *ImageHasher) {
+		h.maxFileSize = n
+	}
+}
+
+// NewImageHasher returns an image hasher that computes the given hash
+// algorithms.
+func NewImageHasher(algorithms HashAlgorithm, options ...ImageHasherOption) *ImageHasher {
+	h := &ImageHasher{
+		algorithms: algorithms,
+		workers:    2,
+	}
+	for _, option := range options {
+		option(h)
+	}
+	if h.cache == nil {
+		h.cache = newImageCache[ImageHashes](4096)
+	}
+	return h
+}
+
+// defaultImageHasher is the image hasher used by CollectHashes.
+var defaultImageHasher = NewImageHasher(HashSHA256)
+
+// Apply applies the image hasher to the collection.
+func (h *ImageHasher) Apply(col *Collection) {
+	// Determine the image path of each process
+	paths := make(map[string][]int) // Maps image paths to processes
+	col.forEach(CollectHashes, func(proc *Process) {
+		if proc.ImagePath != "" {
+			return
+		}
+		ref, err := col.open(proc.ID)
+		if err != nil {
+			proc.fail(CollectHashes, "open", err)
+			return
+		}
+		defer ref.Close()
+		if path, err := ref.ImagePath(); err == nil {
+			proc.ImagePath = path
+		} else {
+			proc.fail(CollectHashes, "image path", err)
+		}
+	})
+	for i := range col.Procs {
+		if !col.Excluded[i] && col.Procs[i].ImagePath != "" {
+			path := col.Procs[i].ImagePath
+			paths[path] = append(paths[path], i)
+		}
+	}
+
+	// Hash each image once
+	work := make(chan string)
+	var wg sync.WaitGroup
+	wg.Add(h.workers)
+	for w := 0; w < h.workers; w++ {
+		go func() {
+			defer wg.Done()
+			for path := range work {
+				hashes, err := h.cache.Lookup(path, h.hash)
+				if err != nil {
+					for _, i := range paths[path] {
+						col.Procs[i].fail(CollectHashes, "hash", err)
+					}
+					continue
+				}
+				for _, i := range paths[path] {
+					col.Procs[i].Hashes = hashes
+				}
+			}
+		}()
+	}
+	ctx := col.context()
+	for path := range paths {
+		if err := ctx.Err(); err != nil {
+			if err == context.DeadlineExceeded {
+				err = ErrTimeout
+			}
+			for _, i := range paths[path] {
+				col.Procs[i].fail(CollectHashes, "hash", err)
+			}
+			continue
+		}
+		work <- path
+	}
+	close(work)
+	wg.Wait()
+}
+
+// hash reads the file at path and computes its hashes.
+func (h *ImageHasher) hash(path string) (ImageHashes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ImageHashes{}, err
+	}
+	defer f.Close()
+
+	if h.maxFileSize > 0 {
+		fi, err := f.Stat()
+		if err != nil {
+			return ImageHashes{}, err
+		}
+		if fi.Size() > h.maxFileSize {
+			return ImageHashes{}, ErrImageTooLarge
+		}
+	}
+
+	var (
+		writers              []io.Writer
+		sha256h, sha1h, md5h hash.Hash
+	)
+	if h.algorithms.Contains(HashSHA256) {
+		sha256h = sha256.New()
+		writers = append(writers, sha256h)
+	}
+	if h.algorithms.Contains(HashSHA1) {
+		sha1h = sha1.New()
+		writers = append(writers, sha1h)
+	}
+	if h.algorithms.Contains(HashMD5) {
+		md5h = md5.New()
+		writers = append(writers, md5h)
+	}
+	if len(writers) == 0 {
+		return ImageHashes{}, nil
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return ImageHashes{}, err
+	}
+
+	var hashes ImageHashes
+	if sha256h != nil {
+		hashes.SHA256 = hex.EncodeToString(sha256h.Sum(nil))
+	}
+	if sha1h != nil {
+		hashes.SHA1 = hex.EncodeToString(sha1h.Sum(nil))
+	}
+	if md5h != nil {
+		hashes.MD5 = hex.EncodeToString(md5h.Sum(nil))
+	}
+	return hashes, nil
+}
@@ -0,0 +1,92 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+// writeImage writes content to a file in a temporary directory and
+// returns its path.
+func writeImage(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImageHasher(t *testing.T) {
+	const content = "MZ this is not really an executable"
+	path := writeImage(t, "fake.exe", content)
+
+	col := winproc.Collection{
+		Procs: []winproc.Process{
+			{ID: 10, ImagePath: path},
+			{ID: 20, ImagePath: path},
+			{ID: 30, ImagePath: path},
+		},
+		Excluded: []bool{false, false, true},
+	}
+
+	winproc.NewImageHasher(winproc.HashSHA256 | winproc.HashSHA1 | winproc.HashMD5).Apply(&col)
+
+	sha256sum := sha256.Sum256([]byte(content))
+	sha1sum := sha1.Sum([]byte(content))
+	md5sum := md5.Sum([]byte(content))
+	expected := winproc.ImageHashes{
+		SHA256: hex.EncodeToString(sha256sum[:]),
+		SHA1:   hex.EncodeToString(sha1sum[:]),
+		MD5:    hex.EncodeToString(md5sum[:]),
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := col.Procs[i].Err(winproc.CollectHashes); err != nil {
+			t.Errorf("PID %d: %v", col.Procs[i].ID, err)
+		}
+		if col.Procs[i].Hashes != expected {
+			t.Errorf("PID %d: hashes are %+v, expected %+v", col.Procs[i].ID, col.Procs[i].Hashes, expected)
+		}
+	}
+	if col.Procs[2].Hashes != (winproc.ImageHashes{}) {
+		t.Errorf("PID 30: an excluded process was hashed")
+	}
+}
+
+func TestImageHasherErrors(t *testing.T) {
+	large := writeImage(t, "large.exe", "more than eight bytes")
+	missing := filepath.Join(t.TempDir(), "missing.exe")
+
+	col := winproc.Collection{
+		Procs: []winproc.Process{
+			{ID: 10, ImagePath: large},
+			{ID: 20, ImagePath: missing},
+		},
+		Excluded: []bool{false, false},
+	}
+
+	winproc.NewImageHasher(winproc.HashSHA256, winproc.HashMaxFileSize(8)).Apply(&col)
+
+	if err := col.Procs[0].Err(winproc.CollectHashes); !errors.Is(err, winproc.ErrImageTooLarge) {
+		t.Errorf("large.exe: expected %v, got %v", winproc.ErrImageTooLarge, err)
+	}
+	if err := col.Procs[1].Err(winproc.CollectHashes); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing.exe: expected %v, got %v", os.ErrNotExist, err)
+	}
+	for _, proc := range col.Procs {
+		if proc.Hashes != (winproc.ImageHashes{}) {
+			t.Errorf("PID %d: unexpected hashes %+v", proc.ID, proc.Hashes)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"io"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/psapi"
+)
+
+// HeapSummary holds summary information about the heaps of a process.
+type HeapSummary struct {
+	Heaps     int    // Number of heaps
+	Blocks    int    // Number of allocated blocks
+	Allocated uint64 // Total size of allocated blocks in bytes, not the commit charge
+}
+
+// heapSummary walks the heaps of the process with the given ID and
+// summarizes them.
+//
+// Walking a heap is slow. Each block is visited individually and the
+// process is examined while it is running, so the summary is only an
+// approximation for processes that are actively allocating memory.
+func heapSummary(pid uint32) (summary HeapSummary, err error) {
+	snapshot, err := psapi.CreateSnapshot(psapi.SnapHeapList, pid)
+	if err != nil {
+		return HeapSummary{}, err
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var list psapi.HeapListEntry
+	for list, err = psapi.FirstHeapList(snapshot); err == nil; list, err = psapi.NextHeapList(snapshot) {
+		summary.Heaps++
+
+		var block psapi.HeapEntry
+		for block, err = psapi.FirstHeap(list.ProcessID, list.HeapID); err == nil; block, err = psapi.NextHeap(block) {
+			if block.Free() {
+				continue
+			}
+			summary.Blocks++
+			summary.Allocated += uint64(block.BlockSize)
+		}
+		if err != io.EOF {
+			return HeapSummary{}, err
+		}
+	}
+	if err != io.EOF {
+		return HeapSummary{}, err
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,104 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// imageKey identifies a particular revision of an image file.
+type imageKey struct {
+	Path    string // Lower case, because windows paths are case-insensitive
+	Size    int64
+	ModTime int64
+}
+
+// statImage returns a key for the current revision of the file at path.
+func statImage(path string) (imageKey, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return imageKey{}, err
+	}
+	return imageKey{
+		Path:    strings.ToLower(path),
+		Size:    fi.Size(),
+		ModTime: fi.ModTime().UnixNano(),
+	}, nil
+}
+
+// imageCache holds values derived from image files, such as version
+// information. Entries are keyed by path, size and modification time, so
+// a file that is replaced will be examined again.
+//
+// It is safe for concurrent use. Concurrent lookups for the same file
+// share a single load.
+type imageCache[T any] struct {
+	mutex   sync.Mutex
+	entries map[imageKey]T
+	loading map[imageKey]*imageLoad[T]
+	limit   int
+}
+
+// imageLoad is a load that is in progress.
+type imageLoad[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// newImageCache returns an image cache that holds up to limit entries.
+func newImageCache[T any](limit int) *imageCache[T] {
+	return &imageCache[T]{
+		entries: make(map[imageKey]T),
+		loading: make(map[imageKey]*imageLoad[T]),
+		limit:   limit,
+	}
+}
+
+// Lookup returns the cached value for the file at path. If the cache does
+// not hold a value for the current revision of the file, load is called
+// and its result is stored unless it returns an error.
+func (cache *imageCache[T]) Lookup(path string, load func(path string) (T, error)) (value T, err error) {
+	key, err := statImage(path)
+	if err != nil {
+		return value, err
+	}
+
+	cache.mutex.Lock()
+	if value, found := cache.entries[key]; found {
+		cache.mutex.Unlock()
+		return value, nil
+	}
+	if pending, found := cache.loading[key]; found {
+		cache.mutex.Unlock()
+		<-pending.done
+		return pending.value, pending.err
+	}
+	pending := &imageLoad[T]{done: make(chan struct{})}
+	cache.loading[key] = pending
+	cache.mutex.Unlock()
+
+	// Load the value without holding the lock
+	pending.value, pending.err = load(path)
+	close(pending.done)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	delete(cache.loading, key)
+	if pending.err != nil {
+		return pending.value, pending.err
+	}
+	if len(cache.entries) >= cache.limit {
+		// Evict an arbitrary entry to make room
+		for k := range cache.entries {
+			delete(cache.entries, k)
+			break
+		}
+	}
+	cache.entries[key] = pending.value
+
+	return pending.value, nil
+}
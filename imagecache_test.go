@@ -0,0 +1,138 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestImageCacheSharedLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.exe")
+	if err := os.WriteFile(path, []byte("image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		cache   = newImageCache[int](8)
+		loads   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	load := func(string) (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const lookups = 8
+	wg.Add(lookups)
+	for i := 0; i < lookups; i++ {
+		go func() {
+			defer wg.Done()
+			if value, err := cache.Lookup(path, load); err != nil || value != 42 {
+				t.Errorf("lookup returned %d, %v", value, err)
+			}
+		}()
+	}
+
+	// Give the lookups a chance to queue up behind the first load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("the image was loaded %d times", n)
+	}
+}
+
+func TestImageCacheRevisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.exe")
+	if err := os.WriteFile(path, []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := newImageCache[string](8)
+	load := func(path string) (string, error) {
+		b, err := os.ReadFile(path)
+		return string(b), err
+	}
+
+	if value, _ := cache.Lookup(path, load); value != "first" {
+		t.Fatalf("expected first, got %q", value)
+	}
+
+	// A replaced file is examined again
+	if err := os.WriteFile(path, []byte("second revision"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := cache.Lookup(path, load); value != "second revision" {
+		t.Fatalf("expected second revision, got %q", value)
+	}
+
+	// Failed loads are not cached
+	failures := 0
+	fail := func(string) (string, error) {
+		failures++
+		return "", errors.New("load failed")
+	}
+	other := filepath.Join(t.TempDir(), "other.exe")
+	if err := os.WriteFile(other, []byte("other"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache.Lookup(other, fail)
+	cache.Lookup(other, fail)
+	if failures != 2 {
+		t.Fatalf("expected 2 load attempts, got %d", failures)
+	}
+}
+
+func TestImageCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	cache := newImageCache[string](2)
+
+	loads := make(map[string]int)
+	load := func(path string) (string, error) {
+		loads[path]++
+		return path, nil
+	}
+
+	var paths []string
+	for _, name := range []string{"a.exe", "b.exe", "c.exe"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+		if _, err := cache.Lookup(path, load); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := len(cache.entries); n != 2 {
+		t.Fatalf("the cache holds %d entries, expected 2", n)
+	}
+
+	// Looking everything up again reloads at least the evicted entry
+	for _, path := range paths {
+		if _, err := cache.Lookup(path, load); err != nil {
+			t.Fatal(err)
+		}
+	}
+	total := 0
+	for _, n := range loads {
+		total += n
+	}
+	if total < 4 {
+		t.Fatalf("expected an evicted entry to be reloaded, got %d loads", total)
+	}
+	if n := len(cache.entries); n > 2 {
+		t.Fatalf("the cache holds %d entries, expected no more than 2", n)
+	}
+}
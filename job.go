@@ -0,0 +1,154 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc/jobaccess"
+	"github.com/gentlemanautomaton/winproc/jobapi"
+	"github.com/gentlemanautomaton/winproc/nativeapi"
+)
+
+// JobInfo holds information about the job object that a process belongs to.
+//
+// Windows does not provide a way to determine which job a process belongs
+// to from the process alone. The Name, Accounting and Limits fields are only
+// populated for jobs identified by the IdentifyJobs collection option.
+type JobInfo struct {
+	InJob      bool          // True if the process belongs to a job
+	Name       string        // The name of the job, if it was identified
+	Memory     JobMemory     // Commit usage and limits of the job
+	Accounting JobAccounting // Accounting information for an identified job
+	Limits     JobLimits     // Limits of an identified job
+}
+
+// JobMemory holds commit usage and limit information for a job. Values are
+// expressed in bytes.
+type JobMemory struct {
+	SharedCommit      uint64
+	PrivateCommit     uint64
+	PeakPrivateCommit uint64
+	PrivateLimit      uint64
+	TotalLimit        uint64
+}
+
+// JobAccounting holds accounting information for a job.
+type JobAccounting struct {
+	User                time.Duration // Time spent in user mode by all processes
+	Kernel              time.Duration // Time spent in kernel mode by all processes
+	PageFaults          uint32
+	TotalProcesses      uint32
+	ActiveProcesses     uint32
+	TerminatedProcesses uint32
+}
+
+// JobLimits holds the limits that apply to a job.
+type JobLimits struct {
+	Flags           jobapi.LimitFlags
+	ActiveProcesses uint32 // Only valid with jobapi.LimitActiveProcess
+	ProcessMemory   uint64 // Only valid with jobapi.LimitProcessMemory
+	JobMemory       uint64 // Only valid with jobapi.LimitJobMemory
+	PeakProcessUsed uint64
+	PeakJobUsed     uint64
+}
+
+// IdentifyJobs returns a collection option that identifies processes that
+// belong to any of the named job objects. It records the name, accounting
+// and limits of the job for each member.
+//
+// Jobs that cannot be opened are skipped.
+func IdentifyJobs(names ...string) CollectionOption {
+	return jobIdentifier(names)
+}
+
+type jobIdentifier []string
+
+// Apply applies the job identifier to the collection.
+func (names jobIdentifier) Apply(col *Collection) {
+	for _, name := range names {
+		job, err := jobapi.OpenJobObject(name, jobaccess.Query)
+		if err != nil {
+			continue
+		}
+		info, pids, err := queryJob(job)
+		syscall.CloseHandle(job)
+		if err != nil {
+			continue
+		}
+		info.Name = name
+
+		members := make(map[ID]bool, len(pids))
+		for _, pid := range pids {
+			members[ID(pid)] = true
+		}
+
+		for i := range col.Procs {
+			if col.Excluded[i] || !members[col.Procs[i].ID] {
+				continue
+			}
+			proc := &col.Procs[i]
+			info.Memory = proc.Job.Memory
+			proc.Job = info
+		}
+	}
+}
+
+// queryJob returns accounting and limit information for a job along with
+// the IDs of its member processes.
+func queryJob(job syscall.Handle) (info JobInfo, pids []uint32, err error) {
+	accounting, err := jobapi.QueryBasicAccounting(job)
+	if err != nil {
+		return JobInfo{}, nil, err
+	}
+	limits, err := jobapi.QueryExtendedLimits(job)
+	if err != nil {
+		return JobInfo{}, nil, err
+	}
+	pids, err = jobapi.QueryProcessIDs(job)
+	if err != nil {
+		return JobInfo{}, nil, err
+	}
+
+	return JobInfo{
+		InJob:      true,
+		Accounting: JobAccountingFromInfo(accounting),
+		Limits:     jobLimitsFromInfo(limits),
+	}, pids, nil
+}
+
+// JobAccountingFromInfo converts basic accounting information returned by
+// the jobapi package to a JobAccounting value.
+func JobAccountingFromInfo(info jobapi.BasicAccountingInfo) JobAccounting {
+	return JobAccounting{
+		User:                time.Duration(info.TotalUserTime) * 100,
+		Kernel:              time.Duration(info.TotalKernelTime) * 100,
+		PageFaults:          info.TotalPageFaultCount,
+		TotalProcesses:      info.TotalProcesses,
+		ActiveProcesses:     info.ActiveProcesses,
+		TerminatedProcesses: info.TotalTerminatedProcesses,
+	}
+}
+
+func jobLimitsFromInfo(info jobapi.ExtendedLimitInfo) JobLimits {
+	return JobLimits{
+		Flags:           info.BasicLimitInfo.LimitFlags,
+		ActiveProcesses: info.BasicLimitInfo.ActiveProcessLimit,
+		ProcessMemory:   uint64(info.ProcessMemoryLimit),
+		JobMemory:       uint64(info.JobMemoryLimit),
+		PeakProcessUsed: uint64(info.PeakProcessMemoryUsed),
+		PeakJobUsed:     uint64(info.PeakJobMemoryUsed),
+	}
+}
+
+func jobMemoryFromInfo(info nativeapi.JobMemoryInfo) JobMemory {
+	return JobMemory{
+		SharedCommit:      info.SharedCommitUsage,
+		PrivateCommit:     info.PrivateCommitUsage,
+		PeakPrivateCommit: info.PeakPrivateCommitUsage,
+		PrivateLimit:      info.PrivateCommitLimit,
+		TotalLimit:        info.TotalCommitLimit,
+	}
+}
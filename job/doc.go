@@ -0,0 +1,8 @@
+// Package job manages windows job objects. It can be used to contain,
+// limit and terminate groups of processes as a unit.
+//
+// Processes created by a member of a job become members of the same job
+// unless breakaway is permitted. Assigning the root of a process tree to a
+// job before it starts children is therefore enough to capture the whole
+// tree.
+package job
@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package job
+
+import "errors"
+
+var (
+	// ErrClosed is returned when a job handle needed for an action has
+	// already been closed.
+	ErrClosed = errors.New("the job handle has been closed")
+)
@@ -0,0 +1,271 @@
+//go:build windows
+// +build windows
+
+package job
+
+import (
+	"sync"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/jobaccess"
+	"github.com/gentlemanautomaton/winproc/jobapi"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+// A Job is a reference to a windows job object. It manages an open system
+// handle internally.
+//
+// Each job must be closed when it is no longer needed. If the job was
+// created with the KillOnClose limit, closing the last handle to it will
+// terminate all of its processes.
+type Job struct {
+	mutex  sync.RWMutex
+	handle syscall.Handle
+	name   string
+}
+
+// Create creates a job object with the given name. If name is empty an
+// anonymous job object is created.
+//
+// If a job object with the given name already exists it will be opened
+// instead.
+//
+// It is the caller's responsibility to close the job when finished with it.
+func Create(name string) (*Job, error) {
+	handle, err := jobapi.CreateJobObject(name)
+	if err != nil && err != syscall.ERROR_ALREADY_EXISTS {
+		return nil, err
+	}
+	return &Job{handle: handle, name: name}, nil
+}
+
+// Open returns a reference to the existing job object with the given name
+// and access rights.
+//
+// If one or more access rights are provided, they will be combined. If no
+// access rights are provided, the AllAccess right will be used.
+//
+// It is the caller's responsibility to close the job when finished with it.
+func Open(name string, rights ...jobaccess.Rights) (*Job, error) {
+	var combinedRights jobaccess.Rights
+	if len(rights) > 0 {
+		for _, r := range rights {
+			combinedRights |= r
+		}
+	} else {
+		combinedRights = jobaccess.AllAccess
+	}
+
+	handle, err := jobapi.OpenJobObject(name, combinedRights)
+	if err != nil {
+		return nil, err
+	}
+	return &Job{handle: handle, name: name}, nil
+}
+
+// Name returns the name of the job. It returns an empty string for
+// anonymous jobs.
+func (job *Job) Name() string {
+	return job.name
+}
+
+// Assign assigns the process referenced by ref to the job.
+//
+// The reference must have been opened with the SetQuota and Terminate
+// access rights.
+func (job *Job) Assign(ref *winproc.Ref) error {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	return ref.AssignToJob(job.handle)
+}
+
+// AssignTree assigns every process in the given tree to the job. Parents
+// are assigned before their children.
+//
+// Processes that have already exited are skipped. Assignment stops at the
+// first error that is encountered.
+func (job *Job) AssignTree(tree []winproc.Node) error {
+	for _, node := range tree {
+		ref, err := node.Ref(processaccess.SetQuota, processaccess.Terminate)
+		if err == nil {
+			err = job.Assign(ref)
+			ref.Close()
+			if err != nil {
+				return err
+			}
+		}
+		if err := job.AssignTree(node.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Contains returns true if the process referenced by ref is a member of the
+// job.
+func (job *Job) Contains(ref *winproc.Ref) (bool, error) {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return false, ErrClosed
+	}
+
+	return ref.MemberOf(job.handle)
+}
+
+// Limits returns the limits that currently apply to the job.
+func (job *Job) Limits() (Limits, error) {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return Limits{}, ErrClosed
+	}
+
+	info, err := jobapi.QueryExtendedLimits(job.handle)
+	if err != nil {
+		return Limits{}, err
+	}
+
+	// CPU rate control is not available on older versions of windows
+	rate, _ := jobapi.QueryCPURateControl(job.handle)
+
+	return limitsFromInfo(info, rate), nil
+}
+
+// SetLimits applies the given limits to the job. Any limits that are not
+// described by Limits are left unchanged.
+func (job *Job) SetLimits(limits Limits) error {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	info, err := jobapi.QueryExtendedLimits(job.handle)
+	if err != nil {
+		return err
+	}
+	limits.apply(&info)
+	if err := jobapi.SetExtendedLimits(job.handle, info); err != nil {
+		return err
+	}
+
+	if limits.CPURate > 0 {
+		return jobapi.SetCPURateControl(job.handle, jobapi.CPURateControlInfo{
+			ControlFlags: jobapi.CPURateControlEnable | jobapi.CPURateControlHardCap,
+			Rate:         limits.CPURate,
+		})
+	}
+
+	// Remove an existing CPU rate cap, if there is one
+	if rate, err := jobapi.QueryCPURateControl(job.handle); err == nil && rate.ControlFlags != 0 {
+		return jobapi.SetCPURateControl(job.handle, jobapi.CPURateControlInfo{})
+	}
+
+	return nil
+}
+
+// Accounting returns accounting information for the job.
+func (job *Job) Accounting() (winproc.JobAccounting, error) {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return winproc.JobAccounting{}, ErrClosed
+	}
+
+	info, err := jobapi.QueryBasicAccounting(job.handle)
+	if err != nil {
+		return winproc.JobAccounting{}, err
+	}
+
+	return winproc.JobAccountingFromInfo(info), nil
+}
+
+// ProcessIDs returns the IDs of the processes that are members of the job.
+func (job *Job) ProcessIDs() ([]winproc.ID, error) {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return nil, ErrClosed
+	}
+
+	pids, err := jobapi.QueryProcessIDs(job.handle)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]winproc.ID, len(pids))
+	for i, pid := range pids {
+		ids[i] = winproc.ID(pid)
+	}
+	return ids, nil
+}
+
+// Processes returns a list of the processes that are members of the job.
+// Collection options can be provided to filter the list and collect
+// additional process information. They will be evaluated after the list
+// has been limited to members of the job.
+func (job *Job) Processes(options ...winproc.CollectionOption) ([]winproc.Process, error) {
+	ids, err := job.ProcessIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[winproc.ID]bool, len(ids))
+	for _, id := range ids {
+		members[id] = true
+	}
+
+	opts := make([]winproc.CollectionOption, 0, len(options)+1)
+	opts = append(opts, winproc.Include(func(process winproc.Process) bool {
+		return members[process.ID]
+	}))
+	opts = append(opts, options...)
+
+	return winproc.List(opts...)
+}
+
+// Terminate terminates all processes in the job with the given exit code.
+//
+// Processes are terminated atomically by the operating system, so children
+// spawned while the job is being terminated cannot escape it.
+func (job *Job) Terminate(exitCode uint32) error {
+	job.mutex.RLock()
+	defer job.mutex.RUnlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	return jobapi.TerminateJobObject(job.handle, exitCode)
+}
+
+// Close releases the job handle maintained by job.
+//
+// If job has already been closed it will return ErrClosed.
+func (job *Job) Close() error {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if job.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	if err := syscall.CloseHandle(job.handle); err != nil {
+		return err
+	}
+	job.handle = syscall.InvalidHandle
+
+	return nil
+}
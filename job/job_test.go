@@ -0,0 +1,67 @@
+//go:build windows
+// +build windows
+
+package job_test
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/job"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+func TestTerminate(t *testing.T) {
+	j, err := job.Create("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	if err := j.SetLimits(job.Limits{KillOnClose: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	command := exec.Command("notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	ref, err := winproc.Open(winproc.ID(command.Process.Pid), processaccess.SetQuota, processaccess.Terminate, processaccess.Synchronize, processaccess.QueryLimitedInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if err := j.Assign(ref); err != nil {
+		t.Fatal(err)
+	}
+
+	if member, err := j.Contains(ref); err != nil {
+		t.Fatal(err)
+	} else if !member {
+		t.Fatalf("process %d is not a member of the job", command.Process.Pid)
+	}
+
+	ids, err := j.ProcessIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != winproc.ID(command.Process.Pid) {
+		t.Errorf("unexpected job members: %v", ids)
+	}
+
+	if err := j.Terminate(1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := ref.Wait(ctx); err != nil {
+		t.Error(err)
+	}
+}
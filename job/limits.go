@@ -0,0 +1,102 @@
+//go:build windows
+// +build windows
+
+package job
+
+import "github.com/gentlemanautomaton/winproc/jobapi"
+
+// Limits describe the limits that apply to a job. The zero value of each
+// field means that the associated limit is not enforced.
+type Limits struct {
+	// KillOnClose causes all processes in the job to be terminated when
+	// the last handle to the job is closed.
+	KillOnClose bool
+
+	// ActiveProcesses is the maximum number of simultaneously active
+	// processes in the job.
+	ActiveProcesses uint32
+
+	// ProcessMemory is the maximum amount of memory in bytes that each
+	// process in the job may commit.
+	ProcessMemory uint64
+
+	// JobMemory is the maximum amount of memory in bytes that all processes
+	// in the job may commit in total.
+	JobMemory uint64
+
+	// CPURate is a hard cap on the processor cycles that the job may use,
+	// expressed in hundredths of a percent. A value of 10000 represents
+	// 100 percent of all processors.
+	CPURate uint32
+
+	// BreakawayOK allows processes in the job to create child processes
+	// outside of the job by specifying CREATE_BREAKAWAY_FROM_JOB.
+	BreakawayOK bool
+
+	// SilentBreakawayOK causes child processes to be created outside of the
+	// job automatically.
+	SilentBreakawayOK bool
+}
+
+// managedFlags are the limit flags that are controlled by Limits.
+const managedFlags = jobapi.LimitKillOnJobClose |
+	jobapi.LimitActiveProcess |
+	jobapi.LimitProcessMemory |
+	jobapi.LimitJobMemory |
+	jobapi.LimitBreakawayOK |
+	jobapi.LimitSilentBreakawayOK
+
+// apply updates info so that it enforces the limits in l. Flags that are
+// not managed by l are left untouched.
+func (l Limits) apply(info *jobapi.ExtendedLimitInfo) {
+	flags := info.BasicLimitInfo.LimitFlags &^ managedFlags
+
+	if l.KillOnClose {
+		flags |= jobapi.LimitKillOnJobClose
+	}
+	if l.ActiveProcesses > 0 {
+		flags |= jobapi.LimitActiveProcess
+		info.BasicLimitInfo.ActiveProcessLimit = l.ActiveProcesses
+	}
+	if l.ProcessMemory > 0 {
+		flags |= jobapi.LimitProcessMemory
+		info.ProcessMemoryLimit = uintptr(l.ProcessMemory)
+	}
+	if l.JobMemory > 0 {
+		flags |= jobapi.LimitJobMemory
+		info.JobMemoryLimit = uintptr(l.JobMemory)
+	}
+	if l.BreakawayOK {
+		flags |= jobapi.LimitBreakawayOK
+	}
+	if l.SilentBreakawayOK {
+		flags |= jobapi.LimitSilentBreakawayOK
+	}
+
+	info.BasicLimitInfo.LimitFlags = flags
+}
+
+// limitsFromInfo returns the limits described by info and rate.
+func limitsFromInfo(info jobapi.ExtendedLimitInfo, rate jobapi.CPURateControlInfo) (l Limits) {
+	flags := info.BasicLimitInfo.LimitFlags
+
+	l.KillOnClose = flags&jobapi.LimitKillOnJobClose != 0
+	if flags&jobapi.LimitActiveProcess != 0 {
+		l.ActiveProcesses = info.BasicLimitInfo.ActiveProcessLimit
+	}
+	if flags&jobapi.LimitProcessMemory != 0 {
+		l.ProcessMemory = uint64(info.ProcessMemoryLimit)
+	}
+	if flags&jobapi.LimitJobMemory != 0 {
+		l.JobMemory = uint64(info.JobMemoryLimit)
+	}
+	l.BreakawayOK = flags&jobapi.LimitBreakawayOK != 0
+	l.SilentBreakawayOK = flags&jobapi.LimitSilentBreakawayOK != 0
+
+	const hardCap = jobapi.CPURateControlEnable | jobapi.CPURateControlHardCap
+	if rate.ControlFlags&hardCap == hardCap {
+		l.CPURate = rate.Rate
+	}
+
+	return l
+}
@@ -0,0 +1,17 @@
+package jobaccess
+
+// Rights hold a set of windows job object access rights.
+type Rights uint32
+
+// Windows job object access rights.
+//
+// https://docs.microsoft.com/en-us/windows/win32/procthread/job-object-security-and-access-rights
+const (
+	AssignProcess         Rights = 0x00000001 // JOB_OBJECT_ASSIGN_PROCESS
+	SetAttributes         Rights = 0x00000002 // JOB_OBJECT_SET_ATTRIBUTES
+	Query                 Rights = 0x00000004 // JOB_OBJECT_QUERY
+	Terminate             Rights = 0x00000008 // JOB_OBJECT_TERMINATE
+	SetSecurityAttributes Rights = 0x00000010 // JOB_OBJECT_SET_SECURITY_ATTRIBUTES
+	Synchronize           Rights = 0x00100000 // SYNCHRONIZE
+	AllAccess             Rights = 0x001F003F // JOB_OBJECT_ALL_ACCESS
+)
@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package jobapi
+
+// BasicAccountingInfo holds basic accounting information for a job object.
+//
+// Times are expressed in 100-nanosecond intervals.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_basic_accounting_information
+type BasicAccountingInfo struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
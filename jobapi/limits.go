@@ -0,0 +1,93 @@
+//go:build windows
+// +build windows
+
+package jobapi
+
+// LimitFlags hold a set of job object limit flags.
+type LimitFlags uint32
+
+// Job object limit flags.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_basic_limit_information
+const (
+	LimitWorkingSet              LimitFlags = 0x00000001 // JOB_OBJECT_LIMIT_WORKINGSET
+	LimitProcessTime             LimitFlags = 0x00000002 // JOB_OBJECT_LIMIT_PROCESS_TIME
+	LimitJobTime                 LimitFlags = 0x00000004 // JOB_OBJECT_LIMIT_JOB_TIME
+	LimitActiveProcess           LimitFlags = 0x00000008 // JOB_OBJECT_LIMIT_ACTIVE_PROCESS
+	LimitAffinity                LimitFlags = 0x00000010 // JOB_OBJECT_LIMIT_AFFINITY
+	LimitPriorityClass           LimitFlags = 0x00000020 // JOB_OBJECT_LIMIT_PRIORITY_CLASS
+	LimitPreserveJobTime         LimitFlags = 0x00000040 // JOB_OBJECT_LIMIT_PRESERVE_JOB_TIME
+	LimitSchedulingClass         LimitFlags = 0x00000080 // JOB_OBJECT_LIMIT_SCHEDULING_CLASS
+	LimitProcessMemory           LimitFlags = 0x00000100 // JOB_OBJECT_LIMIT_PROCESS_MEMORY
+	LimitJobMemory               LimitFlags = 0x00000200 // JOB_OBJECT_LIMIT_JOB_MEMORY
+	LimitDieOnUnhandledException LimitFlags = 0x00000400 // JOB_OBJECT_LIMIT_DIE_ON_UNHANDLED_EXCEPTION
+	LimitBreakawayOK             LimitFlags = 0x00000800 // JOB_OBJECT_LIMIT_BREAKAWAY_OK
+	LimitSilentBreakawayOK       LimitFlags = 0x00001000 // JOB_OBJECT_LIMIT_SILENT_BREAKAWAY_OK
+	LimitKillOnJobClose          LimitFlags = 0x00002000 // JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE
+	LimitSubsetAffinity          LimitFlags = 0x00004000 // JOB_OBJECT_LIMIT_SUBSET_AFFINITY
+)
+
+// BasicLimitInfo holds the basic limits of a job object.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_basic_limit_information
+type BasicLimitInfo struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              LimitFlags
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+// IOCounters holds I/O accounting information.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-io_counters
+type IOCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+// ExtendedLimitInfo holds the extended limits of a job object.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_extended_limit_information
+type ExtendedLimitInfo struct {
+	BasicLimitInfo        BasicLimitInfo
+	IOInfo                IOCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// CPURateControlFlags hold a set of job object CPU rate control flags.
+type CPURateControlFlags uint32
+
+// Job object CPU rate control flags.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_cpu_rate_control_information
+const (
+	CPURateControlEnable      CPURateControlFlags = 0x00000001 // JOB_OBJECT_CPU_RATE_CONTROL_ENABLE
+	CPURateControlWeightBased CPURateControlFlags = 0x00000002 // JOB_OBJECT_CPU_RATE_CONTROL_WEIGHT_BASED
+	CPURateControlHardCap     CPURateControlFlags = 0x00000004 // JOB_OBJECT_CPU_RATE_CONTROL_HARD_CAP
+	CPURateControlNotify      CPURateControlFlags = 0x00000008 // JOB_OBJECT_CPU_RATE_CONTROL_NOTIFY
+	CPURateControlMinMaxRate  CPURateControlFlags = 0x00000010 // JOB_OBJECT_CPU_RATE_CONTROL_MIN_MAX_RATE
+)
+
+// CPURateControlInfo holds the CPU rate control settings of a job object.
+//
+// When CPURateControlHardCap is set, Rate is the portion of processor cycles
+// that the job may use in each scheduling interval, expressed in hundredths
+// of a percent. A value of 10000 represents 100 percent.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-jobobject_cpu_rate_control_information
+type CPURateControlInfo struct {
+	ControlFlags CPURateControlFlags
+	Rate         uint32 // Holds CpuRate, Weight or MinRate and MaxRate
+}
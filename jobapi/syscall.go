@@ -0,0 +1,314 @@
+//go:build windows
+// +build windows
+
+package jobapi
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winproc/jobaccess"
+	"github.com/gentlemanautomaton/winproc/jobinfo"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procAssignProcessToJobObject  = modkernel32.NewProc("AssignProcessToJobObject")
+	procCreateJobObject           = modkernel32.NewProc("CreateJobObjectW")
+	procIsProcessInJob            = modkernel32.NewProc("IsProcessInJob")
+	procOpenJobObject             = modkernel32.NewProc("OpenJobObjectW")
+	procQueryInformationJobObject = modkernel32.NewProc("QueryInformationJobObject")
+	procSetInformationJobObject   = modkernel32.NewProc("SetInformationJobObject")
+	procTerminateJobObject        = modkernel32.NewProc("TerminateJobObject")
+)
+
+// CreateJobObject creates or opens a job object with the given name. If name
+// is empty an anonymous job object is created. It calls the CreateJobObjectW
+// windows API function.
+//
+// If a job object with the given name already exists it is opened and
+// syscall.ERROR_ALREADY_EXISTS is returned along with a valid handle.
+//
+// It is the caller's responsibility to close the returned handle when
+// finished with it by calling syscall.CloseHandle().
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi2/nf-jobapi2-createjobobjectw
+func CreateJobObject(name string) (job syscall.Handle, err error) {
+	var name16 *uint16
+	if name != "" {
+		if name16, err = syscall.UTF16PtrFromString(name); err != nil {
+			return 0, err
+		}
+	}
+
+	r0, _, e := syscall.Syscall(
+		procCreateJobObject.Addr(),
+		2,
+		0,
+		uintptr(unsafe.Pointer(name16)),
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+		return 0, err
+	}
+	if e == syscall.ERROR_ALREADY_EXISTS {
+		err = syscall.ERROR_ALREADY_EXISTS
+	}
+	return syscall.Handle(r0), err
+}
+
+// AssignProcess assigns a process to a job object. It calls the
+// AssignProcessToJobObject windows API function.
+//
+// The process handle must have the SetQuota and Terminate access rights.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi2/nf-jobapi2-assignprocesstojobobject
+func AssignProcess(job, process syscall.Handle) (err error) {
+	r0, _, e := syscall.Syscall(
+		procAssignProcessToJobObject.Addr(),
+		2,
+		uintptr(job),
+		uintptr(process),
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// TerminateJobObject terminates all processes currently associated with a
+// job object. It calls the TerminateJobObject windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi2/nf-jobapi2-terminatejobobject
+func TerminateJobObject(job syscall.Handle, exitCode uint32) (err error) {
+	r0, _, e := syscall.Syscall(
+		procTerminateJobObject.Addr(),
+		2,
+		uintptr(job),
+		uintptr(exitCode),
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// IsProcessInJob returns true if the given process is a member of the given
+// job. If job is zero, it returns true if the process is a member of any
+// job. It calls the IsProcessInJob windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi/nf-jobapi-isprocessinjob
+func IsProcessInJob(process, job syscall.Handle) (member bool, err error) {
+	var result int32
+
+	r0, _, e := syscall.Syscall(
+		procIsProcessInJob.Addr(),
+		3,
+		uintptr(process),
+		uintptr(job),
+		uintptr(unsafe.Pointer(&result)))
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+		return false, err
+	}
+	return result != 0, nil
+}
+
+// OpenJobObject opens an existing job object with the given name and access
+// rights. It calls the OpenJobObjectW windows API function.
+//
+// It is the caller's responsibility to close the returned handle when
+// finished with it by calling syscall.CloseHandle().
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi2/nf-jobapi2-openjobobjectw
+func OpenJobObject(name string, rights jobaccess.Rights) (job syscall.Handle, err error) {
+	name16, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.Syscall(
+		procOpenJobObject.Addr(),
+		3,
+		uintptr(rights),
+		0,
+		uintptr(unsafe.Pointer(name16)))
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+		return 0, err
+	}
+	return syscall.Handle(r0), nil
+}
+
+// QueryInformation requests information about a job object. It calls the
+// QueryInformationJobObject windows API function.
+//
+// The type of information to be retrieved is defined by the given
+// information class. If job is zero the job of the calling process is
+// queried.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi2/nf-jobapi2-queryinformationjobobject
+func QueryInformation(job syscall.Handle, class jobinfo.Class, buffer []byte) (n uint32, err error) {
+	if len(buffer) == 0 {
+		return 0, ErrEmptyBuffer
+	}
+
+	r0, _, e := syscall.Syscall6(
+		procQueryInformationJobObject.Addr(),
+		5,
+		uintptr(job),
+		uintptr(class),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(len(buffer)),
+		uintptr(unsafe.Pointer(&n)),
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// SetInformation sets limits for a job object. It calls the
+// SetInformationJobObject windows API function.
+//
+// The type of information to be set is defined by the given information
+// class.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/jobapi2/nf-jobapi2-setinformationjobobject
+func SetInformation(job syscall.Handle, class jobinfo.Class, buffer []byte) (err error) {
+	if len(buffer) == 0 {
+		return ErrEmptyBuffer
+	}
+
+	r0, _, e := syscall.Syscall6(
+		procSetInformationJobObject.Addr(),
+		4,
+		uintptr(job),
+		uintptr(class),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(len(buffer)),
+		0,
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// QueryBasicAccounting returns basic accounting information for a job
+// object. It calls QueryInformation.
+func QueryBasicAccounting(job syscall.Handle) (info BasicAccountingInfo, err error) {
+	const size = unsafe.Sizeof(info)
+	buffer := (*[size]byte)(unsafe.Pointer(&info))
+	_, err = QueryInformation(job, jobinfo.BasicAccountingInfo, buffer[:])
+	return
+}
+
+// QueryExtendedLimits returns the extended limits of a job object. It calls
+// QueryInformation.
+func QueryExtendedLimits(job syscall.Handle) (info ExtendedLimitInfo, err error) {
+	const size = unsafe.Sizeof(info)
+	buffer := (*[size]byte)(unsafe.Pointer(&info))
+	_, err = QueryInformation(job, jobinfo.ExtendedLimitInfo, buffer[:])
+	return
+}
+
+// SetExtendedLimits sets the extended limits of a job object. It calls
+// SetInformation.
+func SetExtendedLimits(job syscall.Handle, info ExtendedLimitInfo) error {
+	const size = unsafe.Sizeof(info)
+	buffer := (*[size]byte)(unsafe.Pointer(&info))
+	return SetInformation(job, jobinfo.ExtendedLimitInfo, buffer[:])
+}
+
+// QueryCPURateControl returns the CPU rate control settings of a job object.
+// It calls QueryInformation.
+//
+// This call is only supported on Windows 8 or newer.
+func QueryCPURateControl(job syscall.Handle) (info CPURateControlInfo, err error) {
+	const size = unsafe.Sizeof(info)
+	buffer := (*[size]byte)(unsafe.Pointer(&info))
+	_, err = QueryInformation(job, jobinfo.CPURateControlInfo, buffer[:])
+	return
+}
+
+// SetCPURateControl sets the CPU rate control settings of a job object.
+// It calls SetInformation.
+//
+// This call is only supported on Windows 8 or newer.
+func SetCPURateControl(job syscall.Handle, info CPURateControlInfo) error {
+	const size = unsafe.Sizeof(info)
+	buffer := (*[size]byte)(unsafe.Pointer(&info))
+	return SetInformation(job, jobinfo.CPURateControlInfo, buffer[:])
+}
+
+// QueryProcessIDs returns the IDs of the processes that are members of a job
+// object. It calls QueryInformation.
+func QueryProcessIDs(job syscall.Handle) (pids []uint32, err error) {
+	// The list is a pair of 32-bit counts followed by an array of pointer
+	// sized process identifiers.
+	type listHeader struct {
+		Assigned uint32
+		Listed   uint32
+	}
+	const (
+		headerSize = unsafe.Sizeof(listHeader{})
+		entrySize  = unsafe.Sizeof(uintptr(0))
+	)
+
+	capacity := 64
+	for round := 0; round < 3; round++ {
+		buffer := make([]uintptr, int(headerSize/entrySize)+capacity)
+		b := unsafe.Slice((*byte)(unsafe.Pointer(&buffer[0])), len(buffer)*int(entrySize))
+
+		_, err = QueryInformation(job, jobinfo.BasicProcessIDList, b)
+		header := (*listHeader)(unsafe.Pointer(&buffer[0]))
+		switch err {
+		case nil:
+			entries := buffer[headerSize/entrySize:][:header.Listed]
+			pids = make([]uint32, len(entries))
+			for i, entry := range entries {
+				pids[i] = uint32(entry)
+			}
+			return pids, nil
+		case syscall.Errno(windows.ERROR_MORE_DATA):
+			// Processes may join the job between calls, so leave a
+			// little room to grow
+			capacity = int(header.Assigned) + 16
+		default:
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
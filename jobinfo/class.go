@@ -0,0 +1,26 @@
+package jobinfo
+
+// Class is a windows job object information class.
+type Class uint32
+
+// Windows job object information classes.
+const (
+	BasicAccountingInfo         Class = 1  // JobObjectBasicAccountingInformation
+	BasicLimitInfo              Class = 2  // JobObjectBasicLimitInformation
+	BasicProcessIDList          Class = 3  // JobObjectBasicProcessIdList
+	BasicUIRestrictions         Class = 4  // JobObjectBasicUIRestrictions
+	SecurityLimitInfo           Class = 5  // JobObjectSecurityLimitInformation
+	EndOfJobTimeInfo            Class = 6  // JobObjectEndOfJobTimeInformation
+	AssociateCompletionPortInfo Class = 7  // JobObjectAssociateCompletionPortInformation
+	BasicAndIOAccountingInfo    Class = 8  // JobObjectBasicAndIoAccountingInformation
+	ExtendedLimitInfo           Class = 9  // JobObjectExtendedLimitInformation
+	_                           Class = 10 // JobObjectJobSetInformation
+	GroupInfo                   Class = 11 // JobObjectGroupInformation
+	NotificationLimitInfo       Class = 12 // JobObjectNotificationLimitInformation
+	LimitViolationInfo          Class = 13 // JobObjectLimitViolationInformation
+	GroupInfoEx                 Class = 14 // JobObjectGroupInformationEx
+	CPURateControlInfo          Class = 15 // JobObjectCpuRateControlInformation
+	NetRateControlInfo          Class = 32 // JobObjectNetRateControlInformation
+	NotificationLimitInfo2      Class = 33 // JobObjectNotificationLimitInformation2
+	LimitViolationInfo2         Class = 34 // JobObjectLimitViolationInformation2
+)
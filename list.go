@@ -3,6 +3,8 @@
 
 package winproc
 
+import "context"
+
 // List returns a list of running processes. Collection options can be
 // provided to filter the list and collect additional process information.
 // Options will be evaluated in order.
@@ -10,6 +12,23 @@ package winproc
 // If a filter relies on process information gathered by one or more
 // collector options, those options must be included before the filter.
 func List(options ...CollectionOption) ([]Process, error) {
+	return ListContext(context.Background(), options...)
+}
+
+// ListContext returns a list of running processes. Collection options can
+// be provided to filter the list and collect additional process information.
+// Options will be evaluated in order.
+//
+// If ctx is cancelled or its deadline passes while information is being
+// collected, the remaining processes will not be examined and a partial
+// list will be returned. Each process that was affected will have an error
+// recorded in its Errors field. The MaxWorkers and ProcessTimeout options
+// can be used to further limit collection.
+func ListContext(ctx context.Context, options ...CollectionOption) ([]Process, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Collect all processes from the system
 	procs, err := scan()
 	if err != nil {
@@ -20,6 +39,7 @@ func List(options ...CollectionOption) ([]Process, error) {
 	col := Collection{
 		Procs:    procs,
 		Excluded: make([]bool, len(procs)),
+		Context:  ctx,
 	}
 
 	// Apply each collection option in order
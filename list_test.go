@@ -4,11 +4,32 @@
 package winproc_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gentlemanautomaton/winproc"
 )
 
+func TestListContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := winproc.ListContext(ctx, winproc.CollectTimes); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestListContextWorkers(t *testing.T) {
+	procs, err := winproc.ListContext(context.Background(), winproc.MaxWorkers(2), winproc.CollectTimes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, proc := range procs {
+		if proc.Err(winproc.CollectTimes) == nil && proc.Times.Creation.IsZero() {
+			t.Errorf("PID %d: creation time was not collected", proc.ID)
+		}
+	}
+}
+
 func BenchmarkList(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		winproc.List()
@@ -39,14 +60,63 @@ func BenchmarkListWithTimes(b *testing.B) {
 	}
 }
 
+func BenchmarkListWithCycleTime(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.CollectCycleTime)
+	}
+}
+
 func BenchmarkListWithCriticality(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		winproc.List(winproc.CollectCriticality)
 	}
 }
 
+func BenchmarkListWithJobs(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.CollectJobs)
+	}
+}
+
+func BenchmarkListWithPackages(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.CollectPackages)
+	}
+}
+
+func BenchmarkListWithFileVersion(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.CollectFileVersion)
+	}
+}
+
+func BenchmarkListWithSignatures(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.CollectSignatures)
+	}
+}
+
+func BenchmarkListWithHashes(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.CollectHashes)
+	}
+}
+
 func BenchmarkListWithAll(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		winproc.List(winproc.CollectCommands, winproc.CollectSessions, winproc.CollectUsers, winproc.CollectTimes, winproc.CollectCriticality)
 	}
 }
+
+func BenchmarkListWithWorkers(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		winproc.List(winproc.MaxWorkers(4), winproc.CollectUsers)
+	}
+}
+
+func BenchmarkListWithCache(b *testing.B) {
+	cache := winproc.NewCache()
+	for n := 0; n < b.N; n++ {
+		winproc.List(cache.Collect(winproc.CollectCommands | winproc.CollectUsers))
+	}
+}
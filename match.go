@@ -67,3 +67,91 @@ func ContainsName(name string) Filter {
 		return strings.Contains(strings.ToLower(process.Name), name)
 	}
 }
+
+// MatchPackageFamily returns a filter that matches processes belonging to
+// a package family case-insensitively.
+//
+// It relies on information gathered by the CollectPackages option.
+func MatchPackageFamily(family string) Filter {
+	return func(process Process) bool {
+		return process.Package.FamilyName != "" && strings.EqualFold(process.Package.FamilyName, family)
+	}
+}
+
+// Unsigned returns a filter that matches processes whose executable image
+// lacks a valid embedded signature. This includes images with invalid
+// signatures and images that are signed through a security catalog.
+//
+// It relies on information gathered by the CollectSignatures option.
+// Processes whose signature could not be examined are not matched.
+func Unsigned() Filter {
+	return func(process Process) bool {
+		switch process.Signing.Status {
+		case SignatureNotEmbedded, SignatureInvalid:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// InJob returns a filter that matches processes that belong to a job object.
+//
+// It relies on information gathered by the CollectJobs or IdentifyJobs
+// options.
+func InJob() Filter {
+	return func(process Process) bool {
+		return process.Job.InJob
+	}
+}
+
+// NotInJob returns a filter that matches processes that do not belong to
+// any job object. Processes whose job membership could not be determined
+// are not matched.
+//
+// It relies on information gathered by the CollectJobs option.
+func NotInJob() Filter {
+	return func(process Process) bool {
+		return !process.Job.InJob && process.Err(CollectJobs) == nil
+	}
+}
+
+// MatchJob returns a filter that matches processes belonging to the named
+// job case-insensitively.
+//
+// It relies on information gathered by the IdentifyJobs option.
+func MatchJob(name string) Filter {
+	return func(process Process) bool {
+		return process.Job.Name != "" && strings.EqualFold(process.Job.Name, name)
+	}
+}
+
+// MatchService returns a filter that matches processes hosting a service
+// with a matching name.
+//
+// It relies on information gathered by the CollectServices option.
+func MatchService(matcher StringMatcher) Filter {
+	return func(process Process) bool {
+		for _, service := range process.Services {
+			if matcher(service.Name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasVisibleWindow returns a filter that matches processes that own at
+// least one visible top-level window.
+//
+// It relies on information gathered by the CollectWindows option.
+func HasVisibleWindow() Filter {
+	return func(process Process) bool {
+		for _, window := range process.Windows {
+			if window.Visible {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gentlemanautomaton/winproc/ntstatus"
 	"github.com/gentlemanautomaton/winproc/processinfo"
+	"github.com/gentlemanautomaton/winproc/threadinfo"
 	"golang.org/x/sys/windows"
 )
 
@@ -15,6 +16,9 @@ var (
 	modntdll = windows.NewLazySystemDLL("ntdll.dll")
 
 	procQueryInformationProcess = modntdll.NewProc("NtQueryInformationProcess")
+	procQueryInformationThread  = modntdll.NewProc("NtQueryInformationThread")
+	procSuspendProcess          = modntdll.NewProc("NtSuspendProcess")
+	procResumeProcess           = modntdll.NewProc("NtResumeProcess")
 )
 
 // ProcessCommandLine requests the command line of a process from the
@@ -46,6 +50,32 @@ func ProcessCommandLine(process syscall.Handle) (commandLine string, err error)
 	return "", err
 }
 
+// BasicInfo holds basic information about a process.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winternl/nf-winternl-ntqueryinformationprocess#process_basic_information
+type BasicInfo struct {
+	ExitStatus                   uint32
+	PebBaseAddress               uintptr
+	AffinityMask                 uintptr
+	BasePriority                 int32
+	UniqueProcessID              uintptr
+	InheritedFromUniqueProcessID uintptr
+}
+
+// ProcessBasicInfo requests basic information about a process from the
+// NT kernel. It calls ProcessInfo.
+func ProcessBasicInfo(process syscall.Handle) (info BasicInfo, err error) {
+	const size = unsafe.Sizeof(info)
+
+	var buffer [size]byte
+	_, err = ProcessInfo(process, processinfo.BasicInfo, buffer[:])
+	if err != nil {
+		return BasicInfo{}, err
+	}
+
+	return *(*BasicInfo)(unsafe.Pointer(&buffer[0])), nil
+}
+
 // ProcessSessionID requests the session ID of a process from the
 // NT kernel. It calls ProcessInfo.
 func ProcessSessionID(process syscall.Handle) (sessionID uint32, err error) {
@@ -63,6 +93,52 @@ func ProcessSessionID(process syscall.Handle) (sessionID uint32, err error) {
 	return (*sessionInfo)(unsafe.Pointer(&buffer[0])).SessionID, nil
 }
 
+// ProcessConsoleHost requests the ID of the console host process that serves
+// a process from the NT kernel. It calls ProcessInfo.
+//
+// It returns zero if the process is not attached to a console.
+//
+// This call is only supported on Windows 7 or newer.
+func ProcessConsoleHost(process syscall.Handle) (hostID uint32, err error) {
+	const size = unsafe.Sizeof(uintptr(0))
+
+	var buffer [size]byte
+	_, err = ProcessInfo(process, processinfo.ConsoleHostProcess, buffer[:])
+	if err != nil {
+		return 0, err
+	}
+
+	// The lower two bits of the value are used as flags
+	value := *(*uintptr)(unsafe.Pointer(&buffer[0]))
+	return uint32(value &^ 3), nil
+}
+
+// JobMemoryInfo holds commit usage and limit information for the job object
+// that a process belongs to.
+type JobMemoryInfo struct {
+	SharedCommitUsage      uint64
+	PrivateCommitUsage     uint64
+	PeakPrivateCommitUsage uint64
+	PrivateCommitLimit     uint64
+	TotalCommitLimit       uint64
+}
+
+// ProcessJobMemory requests the job memory information of a process from the
+// NT kernel. It calls ProcessInfo.
+//
+// This call is only supported on Windows 10 or newer.
+func ProcessJobMemory(process syscall.Handle) (info JobMemoryInfo, err error) {
+	const size = unsafe.Sizeof(info)
+
+	var buffer [size]byte
+	_, err = ProcessInfo(process, processinfo.JobMemoryInfo, buffer[:])
+	if err != nil {
+		return JobMemoryInfo{}, err
+	}
+
+	return *(*JobMemoryInfo)(unsafe.Pointer(&buffer[0])), nil
+}
+
 // ProcessInfo requests information about a process from the NT kernel.
 // It calls the NtQueryInformationProcess NT native API function.
 //
@@ -92,3 +168,81 @@ func ProcessInfo(process syscall.Handle, class processinfo.Class, buffer []byte)
 	}
 	return
 }
+
+// ThreadSuspendCount requests the suspend count of a thread from the
+// NT kernel. It calls ThreadInfo.
+//
+// This call is only supported on Windows 8.1 or newer.
+func ThreadSuspendCount(thread syscall.Handle) (count uint32, err error) {
+	const size = unsafe.Sizeof(count)
+
+	var buffer [size]byte
+	_, err = ThreadInfo(thread, threadinfo.SuspendCount, buffer[:])
+	if err != nil {
+		return 0, err
+	}
+
+	return *(*uint32)(unsafe.Pointer(&buffer[0])), nil
+}
+
+// ThreadInfo requests information about a thread from the NT kernel.
+// It calls the NtQueryInformationThread NT native API function.
+//
+// The type of information to be retrieved is defined by the given
+// information class.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winternl/nf-winternl-ntqueryinformationthread
+func ThreadInfo(thread syscall.Handle, class threadinfo.Class, buffer []byte) (n uint32, err error) {
+	if len(buffer) == 0 {
+		return 0, ErrEmptyBuffer
+	}
+	if err := procQueryInformationThread.Find(); err != nil {
+		return 0, err
+	}
+
+	r0, _, _ := syscall.Syscall6(
+		procQueryInformationThread.Addr(),
+		5,
+		uintptr(thread),
+		uintptr(class),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(len(buffer)),
+		uintptr(unsafe.Pointer(&n)),
+		0)
+	if r0 != 0 {
+		err = ntstatus.Value(r0)
+	}
+	return
+}
+
+// SuspendProcess suspends all of the threads in a process. It calls the
+// undocumented NtSuspendProcess NT native API function.
+//
+// The process handle must have the PROCESS_SUSPEND_RESUME access right.
+func SuspendProcess(process syscall.Handle) error {
+	if err := procSuspendProcess.Find(); err != nil {
+		return err
+	}
+
+	r0, _, _ := syscall.Syscall(procSuspendProcess.Addr(), 1, uintptr(process), 0, 0)
+	if r0 != 0 {
+		return ntstatus.Value(r0)
+	}
+	return nil
+}
+
+// ResumeProcess resumes all of the threads in a process. It calls the
+// undocumented NtResumeProcess NT native API function.
+//
+// The process handle must have the PROCESS_SUSPEND_RESUME access right.
+func ResumeProcess(process syscall.Handle) error {
+	if err := procResumeProcess.Find(); err != nil {
+		return err
+	}
+
+	r0, _, _ := syscall.Syscall(procResumeProcess.Addr(), 1, uintptr(process), 0, 0)
+	if r0 != 0 {
+		return ntstatus.Value(r0)
+	}
+	return nil
+}
@@ -7,4 +7,9 @@ package winproc
 type Node struct {
 	Process
 	Children []Node
+
+	// Pseudo is true if the node was attached to its parent for clarity
+	// rather than by actual parentage, such as a console host attached to
+	// the console application it serves.
+	Pseudo bool
 }
@@ -4,5 +4,28 @@ package ntstatus
 //
 // https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-erref/596a1078-e883-4972-9bbc-49e60bebca55
 const (
-	InfoLengthMismatch = Value(0xC0000004) // STATUS_INFO_LENGTH_MISMATCH
+	Breakpoint            = Value(0x80000003) // STATUS_BREAKPOINT
+	InvalidInfoClass      = Value(0xC0000003) // STATUS_INVALID_INFO_CLASS
+	InfoLengthMismatch    = Value(0xC0000004) // STATUS_INFO_LENGTH_MISMATCH
+	AccessViolation       = Value(0xC0000005) // STATUS_ACCESS_VIOLATION
+	InPageError           = Value(0xC0000006) // STATUS_IN_PAGE_ERROR
+	NoMemory              = Value(0xC0000017) // STATUS_NO_MEMORY
+	IllegalInstruction    = Value(0xC000001D) // STATUS_ILLEGAL_INSTRUCTION
+	AccessDenied          = Value(0xC0000022) // STATUS_ACCESS_DENIED
+	InvalidImageFormat    = Value(0xC000007B) // STATUS_INVALID_IMAGE_FORMAT
+	ArrayBoundsExceeded   = Value(0xC000008C) // STATUS_ARRAY_BOUNDS_EXCEEDED
+	FloatDivideByZero     = Value(0xC000008E) // STATUS_FLOAT_DIVIDE_BY_ZERO
+	IntegerDivideByZero   = Value(0xC0000094) // STATUS_INTEGER_DIVIDE_BY_ZERO
+	IntegerOverflow       = Value(0xC0000095) // STATUS_INTEGER_OVERFLOW
+	PrivilegedInstruction = Value(0xC0000096) // STATUS_PRIVILEGED_INSTRUCTION
+	NotSupported          = Value(0xC00000BB) // STATUS_NOT_SUPPORTED
+	StackOverflow         = Value(0xC00000FD) // STATUS_STACK_OVERFLOW
+	ProcessIsTerminating  = Value(0xC000010A) // STATUS_PROCESS_IS_TERMINATING
+	DLLNotFound           = Value(0xC0000135) // STATUS_DLL_NOT_FOUND
+	EntryPointNotFound    = Value(0xC0000139) // STATUS_ENTRYPOINT_NOT_FOUND
+	ControlCExit          = Value(0xC000013A) // STATUS_CONTROL_C_EXIT
+	DLLInitFailed         = Value(0xC0000142) // STATUS_DLL_INIT_FAILED
+	HeapCorruption        = Value(0xC0000374) // STATUS_HEAP_CORRUPTION
+	StackBufferOverrun    = Value(0xC0000409) // STATUS_STACK_BUFFER_OVERRUN
+	AssertionFailure      = Value(0xC0000420) // STATUS_ASSERTION_FAILURE
 )
@@ -1,5 +1,28 @@
 package ntstatus
 
 var descriptions = map[Value]string{
-	InfoLengthMismatch: "STATUS_INFO_LENGTH_MISMATCH",
+	Breakpoint:            "STATUS_BREAKPOINT",
+	InvalidInfoClass:      "STATUS_INVALID_INFO_CLASS",
+	InfoLengthMismatch:    "STATUS_INFO_LENGTH_MISMATCH",
+	AccessViolation:       "STATUS_ACCESS_VIOLATION",
+	InPageError:           "STATUS_IN_PAGE_ERROR",
+	NoMemory:              "STATUS_NO_MEMORY",
+	IllegalInstruction:    "STATUS_ILLEGAL_INSTRUCTION",
+	AccessDenied:          "STATUS_ACCESS_DENIED",
+	InvalidImageFormat:    "STATUS_INVALID_IMAGE_FORMAT",
+	ArrayBoundsExceeded:   "STATUS_ARRAY_BOUNDS_EXCEEDED",
+	FloatDivideByZero:     "STATUS_FLOAT_DIVIDE_BY_ZERO",
+	IntegerDivideByZero:   "STATUS_INTEGER_DIVIDE_BY_ZERO",
+	IntegerOverflow:       "STATUS_INTEGER_OVERFLOW",
+	PrivilegedInstruction: "STATUS_PRIVILEGED_INSTRUCTION",
+	NotSupported:          "STATUS_NOT_SUPPORTED",
+	StackOverflow:         "STATUS_STACK_OVERFLOW",
+	ProcessIsTerminating:  "STATUS_PROCESS_IS_TERMINATING",
+	DLLNotFound:           "STATUS_DLL_NOT_FOUND",
+	EntryPointNotFound:    "STATUS_ENTRYPOINT_NOT_FOUND",
+	ControlCExit:          "STATUS_CONTROL_C_EXIT",
+	DLLInitFailed:         "STATUS_DLL_INIT_FAILED",
+	HeapCorruption:        "STATUS_HEAP_CORRUPTION",
+	StackBufferOverrun:    "STATUS_STACK_BUFFER_OVERRUN",
+	AssertionFailure:      "STATUS_ASSERTION_FAILURE",
 }
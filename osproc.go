@@ -0,0 +1,121 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/processaccess"
+	"golang.org/x/sys/windows"
+)
+
+// ErrNotStarted is returned when a reference is requested for a command
+// that has not been started.
+var ErrNotStarted = errors.New("the command has not been started")
+
+// FromProcess returns a reference to p with the given access rights. If no
+// access rights are provided, the QueryLimitedInformation right will be
+// used.
+//
+// The process is opened by its ID. The os package holds a handle to p
+// until it has been waited on or released, which prevents the ID from
+// being recycled, so the reference is only known to belong to p if that
+// handle is still held after the process has been opened. FromProcess
+// checks this and returns ErrProcessGone if p has already been waited on
+// or released, or if p is nil.
+//
+// It is the caller's responsibility to close the reference when finished
+// with it.
+func FromProcess(p *os.Process, rights ...processaccess.Rights) (*Ref, error) {
+	if p == nil {
+		return nil, ErrProcessGone
+	}
+
+	ref, err := Open(ID(p.Pid), rights...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The os package doesn't support signal 0 on windows, but it reports
+	// whether p has been waited on or released before rejecting it
+	if err := p.Signal(syscall.Signal(0)); errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.EINVAL) {
+		ref.Close()
+		return nil, ErrProcessGone
+	}
+
+	return ref, nil
+}
+
+// FromCmd returns a reference to the process started by cmd with the given
+// access rights. If no access rights are provided, the
+// QueryLimitedInformation right will be used.
+//
+// FromCmd must be called after cmd.Start and before cmd.Wait. If cmd has
+// not been started it returns ErrNotStarted. If cmd has already been
+// waited on it returns ErrProcessGone, because the process ID may have been
+// reused.
+//
+// It is the caller's responsibility to close the reference when finished
+// with it.
+func FromCmd(cmd *exec.Cmd, rights ...processaccess.Rights) (*Ref, error) {
+	if cmd.Process == nil {
+		return nil, ErrNotStarted
+	}
+	if cmd.ProcessState != nil {
+		return nil, ErrProcessGone
+	}
+	return FromProcess(cmd.Process, rights...)
+}
+
+// FromHandle returns a reference to the process identified by handle. The
+// handle is duplicated with the same access rights, so the caller retains
+// ownership of handle and may close it at any time.
+//
+// It is the caller's responsibility to close the reference when finished
+// with it.
+func FromHandle(handle syscall.Handle) (*Ref, error) {
+	dup, err := duplicateHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	// The process ID isn't essential, and can't be determined if the
+	// handle lacks query rights
+	pid, _ := windows.GetProcessId(windows.Handle(dup))
+
+	return &Ref{handle: dup, pid: ID(pid)}, nil
+}
+
+// DuplicateHandle returns a duplicate of the process handle maintained by
+// ref, with the same access rights. It can be used to pass the process to
+// other APIs that accept a handle.
+//
+// It is the caller's responsibility to close the returned handle when
+// finished with it.
+func (ref *Ref) DuplicateHandle() (syscall.Handle, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return syscall.InvalidHandle, ErrClosed
+	}
+
+	return duplicateHandle(ref.handle)
+}
+
+// duplicateHandle duplicates handle within the current process with the
+// same access rights.
+func duplicateHandle(handle syscall.Handle) (syscall.Handle, error) {
+	self := windows.CurrentProcess()
+
+	var dup windows.Handle
+	err := windows.DuplicateHandle(self, windows.Handle(handle), self, &dup, 0, false, windows.DUPLICATE_SAME_ACCESS)
+	if err != nil {
+		return syscall.InvalidHandle, err
+	}
+	return syscall.Handle(dup), nil
+}
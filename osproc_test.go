@@ -0,0 +1,86 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+func TestFromCmd(t *testing.T) {
+	command := exec.Command("notepad")
+	if _, err := winproc.FromCmd(command); err != winproc.ErrNotStarted {
+		t.Fatalf("expected %v, got %v", winproc.ErrNotStarted, err)
+	}
+
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	ref, err := winproc.FromCmd(command, processaccess.QueryLimitedInformation, processaccess.Synchronize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if pid, err := ref.ID(); err != nil {
+		t.Fatal(err)
+	} else if pid != winproc.ID(command.Process.Pid) {
+		t.Fatalf("expected PID %d, got %d", command.Process.Pid, pid)
+	}
+
+	handle, err := ref.DuplicateHandle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dup, err := winproc.FromHandle(handle)
+	syscall.CloseHandle(handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dup.Close()
+
+	original, err := ref.UniqueID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicated, err := dup.UniqueID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original != duplicated {
+		t.Fatalf("duplicate refers to %s instead of %s", duplicated, original)
+	}
+
+	command.Process.Kill()
+	command.Wait()
+	if _, err := winproc.FromCmd(command); err != winproc.ErrProcessGone {
+		t.Fatalf("expected %v after Wait, got %v", winproc.ErrProcessGone, err)
+	}
+}
+
+func TestFromProcessReleased(t *testing.T) {
+	command := exec.Command("ping", "-n", "30", "127.0.0.1")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := winproc.FromProcess(command.Process, processaccess.QueryLimitedInformation, processaccess.Terminate)
+	if err != nil {
+		command.Process.Kill()
+		t.Fatal(err)
+	}
+	defer ref.Close()
+	defer ref.Terminate(1)
+
+	command.Process.Release()
+	if _, err := winproc.FromProcess(command.Process); err != winproc.ErrProcessGone {
+		t.Fatalf("expected %v after Release, got %v", winproc.ErrProcessGone, err)
+	}
+}
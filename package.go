@@ -0,0 +1,62 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/appmodel"
+)
+
+// Package holds the package identity of a packaged (AppX or MSIX)
+// application.
+type Package struct {
+	FullName       string
+	FamilyName     string
+	AppUserModelID string
+}
+
+// IsZero returns true if p does not describe a package.
+func (p Package) IsZero() bool {
+	return p.FullName == ""
+}
+
+// String returns a string representation of the package.
+func (p Package) String() string {
+	if p.AppUserModelID != "" {
+		return p.AppUserModelID
+	}
+	return p.FullName
+}
+
+// packageFromProcess returns the package identity of a process. It returns
+// a zero value if the process isn't packaged.
+func packageFromProcess(process syscall.Handle) (Package, error) {
+	fullName, err := appmodel.PackageFullName(process)
+	switch err {
+	case nil:
+	case appmodel.ErrNoPackage:
+		return Package{}, nil
+	default:
+		return Package{}, err
+	}
+
+	familyName, err := appmodel.PackageFamilyName(process)
+	if err != nil {
+		return Package{}, err
+	}
+
+	// Packaged processes that aren't applications, such as background
+	// tasks, do not have an application user model ID
+	appID, err := appmodel.ApplicationUserModelID(process)
+	if err != nil && err != appmodel.ErrNoApplication {
+		return Package{}, err
+	}
+
+	return Package{
+		FullName:       fullName,
+		FamilyName:     familyName,
+		AppUserModelID: appID,
+	}, nil
+}
@@ -0,0 +1,43 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+func TestMatchPackageFamily(t *testing.T) {
+	const family = "Microsoft.WindowsCalculator_8wekyb3d8bbwe"
+
+	calculator := winproc.Process{ID: 100, Name: "CalculatorApp.exe", Package: winproc.Package{
+		FullName:       "Microsoft.WindowsCalculator_11.2307.4.0_x64__8wekyb3d8bbwe",
+		FamilyName:     family,
+		AppUserModelID: family + "!App",
+	}}
+	photos := winproc.Process{ID: 200, Name: "Photos.exe", Package: winproc.Package{
+		FullName:   "Microsoft.Windows.Photos_2023.11110.8002.0_x64__8wekyb3d8bbwe",
+		FamilyName: "Microsoft.Windows.Photos_8wekyb3d8bbwe",
+	}}
+	unpackaged := winproc.Process{ID: 300, Name: "notepad.exe"}
+
+	tests := []struct {
+		Family  string
+		Process winproc.Process
+		Match   bool
+	}{
+		{family, calculator, true},
+		{"microsoft.windowscalculator_8WEKYB3D8BBWE", calculator, true},
+		{"Microsoft.WindowsCalculator", calculator, false},
+		{family, photos, false},
+		{family, unpackaged, false},
+		{"", unpackaged, false},
+	}
+	for _, test := range tests {
+		if match := winproc.MatchPackageFamily(test.Family)(test.Process); match != test.Match {
+			t.Errorf("%s: matching %q returned %t, want %t", test.Process.Name, test.Family, match, test.Match)
+		}
+	}
+}
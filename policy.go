@@ -0,0 +1,157 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsafeTarget is matched by errors returned when a safety policy
+// refuses a destructive operation.
+var ErrUnsafeTarget = errors.New("the process is protected by the safety policy")
+
+// Policy is a safety policy that governs destructive operations, such as
+// terminating or suspending a process.
+//
+// The zero value is the default policy. It refuses the following targets:
+//
+//	The process with ID 0
+//	The process with ID 4
+//	Processes that are critical to the system's operation
+//	Processes running as Local System, NT Authority or Network Service
+//	Processes whose user is unknown
+//
+// Unlike Process.Protected, the policy doesn't refuse every process in
+// session 0. Processes in session 0 that run as other accounts, such as
+// the children of a service running as a service account, are permitted.
+//
+// The overrides can be used to permit critical and protected processes.
+// The processes with IDs 0 and 4 are always refused.
+type Policy struct {
+	AllowCritical  bool // Permit processes that are critical to the system
+	AllowProtected bool // Permit protected processes, such as system services
+}
+
+// PolicyError is returned when a safety policy refuses to let a destructive
+// operation proceed.
+type PolicyError struct {
+	ID     ID
+	Name   string
+	Reason string
+}
+
+// Error returns a string representation of the error.
+func (e *PolicyError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("refusing to act on PID %d: %s", e.ID, e.Reason)
+	}
+	return fmt.Sprintf("refusing to act on %s (PID %d): %s", e.Name, e.ID, e.Reason)
+}
+
+// Is returns true if target is ErrUnsafeTarget.
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrUnsafeTarget
+}
+
+// Check returns a *PolicyError if the policy forbids destructive operations
+// on proc.
+//
+// It relies on process information gathered by the CollectUsers and
+// CollectCriticality options. Processes for which the user has not been
+// collected are treated as protected.
+// Criticality can't be inferred from a missing value, so processes listed
+// without CollectCriticality are treated as non-critical. Use CheckRef to
+// examine a running process directly.
+func (p Policy) Check(proc Process) error {
+	refuse := func(reason string) error {
+		return &PolicyError{ID: proc.ID, Name: proc.Name, Reason: reason}
+	}
+
+	switch {
+	case kernelProcess(proc.ID):
+		return refuse("it is a kernel process")
+	case proc.Critical && !p.AllowCritical:
+		return refuse("it is critical to the system")
+	case systemProcess(proc) && !p.AllowProtected:
+		return refuse("it is a protected system process")
+	}
+
+	return nil
+}
+
+// CheckRef returns a *PolicyError if the policy forbids destructive
+// operations on the process referenced by ref. It examines the process
+// itself, so ref needn't have been opened with query rights.
+//
+// If the process can't be examined, or its criticality can't be
+// determined, it is refused unless the policy allows both critical and
+// protected processes.
+func (p Policy) CheckRef(ref *Ref) error {
+	pid, err := ref.processID()
+	if err != nil {
+		return err
+	}
+
+	if kernelProcess(pid) {
+		return p.Check(Process{ID: pid})
+	}
+
+	unknown := func(reason string) error {
+		if p.AllowCritical && p.AllowProtected {
+			return nil
+		}
+		return &PolicyError{ID: pid, Reason: reason}
+	}
+
+	// The process ID can't be recycled while ref is open, so it's safe to
+	// open a second reference with query rights
+	query, err := Open(pid)
+	if err != nil {
+		return unknown("it could not be examined")
+	}
+	defer query.Close()
+
+	proc := Process{ID: pid}
+	if user, err := query.User(); err == nil {
+		proc.User = user
+	}
+	critical, err := query.Critical()
+	if err != nil {
+		return unknown("its criticality could not be determined")
+	}
+	proc.Critical = critical
+
+	return p.Check(proc)
+}
+
+// kernelProcess returns true if pid identifies the System Idle Process or
+// the System Process.
+func kernelProcess(pid ID) bool {
+	return pid == 0 || pid == 4
+}
+
+// systemProcess returns true if proc runs as one of the system accounts,
+// or if its user is unknown.
+func systemProcess(proc Process) bool {
+	return proc.User.SID == "" || proc.User.System()
+}
+
+// TerminateTree returns a tree operation that terminates each process in
+// the tree with the given exit code, subject to the policy. Children are
+// terminated before their parents.
+func (p Policy) TerminateTree(exitCode uint32) TreeOperation {
+	walker := terminateWalker(exitCode)
+	walker.policy = p
+	return walker.run
+}
+
+// SuspendTree is a tree operation that suspends each process in the tree,
+// subject to the policy. It suspends parents before their children, so
+// that a parent can't spawn new children while they are being suspended.
+func (p Policy) SuspendTree(tree []Node) ([]NodeOutcome, error) {
+	walker := suspendWalker()
+	walker.policy = p
+	return walker.run(tree)
+}
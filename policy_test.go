@@ -0,0 +1,100 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+var policyTable = []winproc.Process{
+	{ID: 0, Name: "[System Process]"},
+	{ID: 4, Name: "System", Critical: true},
+	{ID: 612, Name: "csrss.exe", SessionID: 1, Critical: true, User: winproc.User{SID: "S-1-5-18"}},
+	{ID: 700, Name: "wininit.exe", SessionID: 0, Critical: true, User: winproc.User{SID: "S-1-5-18"}},
+	{ID: 1024, Name: "svchost.exe", SessionID: 0, User: winproc.User{SID: "S-1-5-19"}},
+	{ID: 1536, Name: "worker.exe", SessionID: 0, User: winproc.User{SID: "S-1-5-80-1-2-3-4-5"}},
+	{ID: 2048, Name: "unknown.exe", SessionID: 1},
+	{ID: 4096, Name: "notepad.exe", SessionID: 1, User: winproc.User{SID: "S-1-5-21-1-2-3-1001"}},
+	{ID: 4100, Name: "agent.exe", SessionID: 1, Critical: true, User: winproc.User{SID: "S-1-5-21-1-2-3-1001"}},
+}
+
+func TestPolicy(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Policy  winproc.Policy
+		Allowed []winproc.ID
+	}{
+		{"Default", winproc.Policy{}, []winproc.ID{1536, 4096}},
+		{"AllowCritical", winproc.Policy{AllowCritical: true}, []winproc.ID{1536, 4096, 4100}},
+		{"AllowProtected", winproc.Policy{AllowProtected: true}, []winproc.ID{1024, 1536, 2048, 4096}},
+		{"AllowAll", winproc.Policy{AllowCritical: true, AllowProtected: true}, []winproc.ID{612, 700, 1024, 1536, 2048, 4096, 4100}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			allowed := make(map[winproc.ID]bool)
+			for _, id := range test.Allowed {
+				allowed[id] = true
+			}
+			for _, proc := range policyTable {
+				err := test.Policy.Check(proc)
+				switch {
+				case allowed[proc.ID] && err != nil:
+					t.Errorf("%s: unexpected refusal: %v", proc.Name, err)
+				case !allowed[proc.ID] && err == nil:
+					t.Errorf("%s: expected refusal", proc.Name)
+				case err != nil && !errors.Is(err, winproc.ErrUnsafeTarget):
+					t.Errorf("%s: error does not match ErrUnsafeTarget: %v", proc.Name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyTerminateTree(t *testing.T) {
+	tree := winproc.Tree([]winproc.Process{
+		{ID: 4, Name: "System", Critical: true},
+	})
+
+	_, err := winproc.TerminateTree(1)(tree)
+	if !errors.Is(err, winproc.ErrUnsafeTarget) {
+		t.Fatalf("expected the policy to refuse termination, got %v", err)
+	}
+}
+
+func TestPolicyCheckRef(t *testing.T) {
+	pid := winproc.ID(os.Getpid())
+
+	ref, err := winproc.Open(pid, processaccess.Synchronize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if err := (winproc.Policy{AllowCritical: true, AllowProtected: true}).CheckRef(ref); err != nil {
+		t.Fatalf("the permissive policy refused the test process: %v", err)
+	}
+
+	// The live check should agree with a check of collected information
+	procs, err := winproc.List(
+		winproc.Include(winproc.MatchID(pid)),
+		winproc.CollectSessions, winproc.CollectUsers, winproc.CollectCriticality,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 1 {
+		t.Fatalf("expected 1 process, found %d", len(procs))
+	}
+	expected := winproc.Policy{}.Check(procs[0])
+	actual := winproc.Policy{}.CheckRef(ref)
+	if (expected == nil) != (actual == nil) {
+		t.Fatalf("CheckRef returned %v, but Check returned %v", actual, expected)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+// PriorityClass is a windows process priority class.
+type PriorityClass uint32
+
+// Process priority classes.
+const (
+	PriorityIdle        PriorityClass = 0x00000040 // IDLE_PRIORITY_CLASS
+	PriorityBelowNormal PriorityClass = 0x00004000 // BELOW_NORMAL_PRIORITY_CLASS
+	PriorityNormal      PriorityClass = 0x00000020 // NORMAL_PRIORITY_CLASS
+	PriorityAboveNormal PriorityClass = 0x00008000 // ABOVE_NORMAL_PRIORITY_CLASS
+	PriorityHigh        PriorityClass = 0x00000080 // HIGH_PRIORITY_CLASS
+	PriorityRealtime    PriorityClass = 0x00000100 // REALTIME_PRIORITY_CLASS
+)
+
+// String returns a string representation of the priority class.
+func (p PriorityClass) String() string {
+	switch p {
+	case PriorityIdle:
+		return "idle"
+	case PriorityBelowNormal:
+		return "below normal"
+	case PriorityNormal:
+		return "normal"
+	case PriorityAboveNormal:
+		return "above normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityRealtime:
+		return "realtime"
+	default:
+		return "unknown"
+	}
+}
@@ -7,31 +7,74 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gentlemanautomaton/winproc/fileversion"
 	"github.com/gentlemanautomaton/winproc/processaccess"
 )
 
 // Process holds information about a windows process.
 type Process struct {
-	ID          ID
-	ParentID    ID
-	Name        string
-	Path        string
-	Args        []string
-	CommandLine string
-	SessionID   uint32
-	User        User
-	Threads     int
-	Times       Times
-	Critical    bool
+	ID            ID
+	ParentID      ID
+	Name          string
+	ImagePath     string // The full path of the executable image
+	Path          string
+	Args          []string
+	CommandLine   string
+	SessionID     uint32
+	User          User
+	Threads       int
+	Times         Times
+	Critical      bool
+	Job           JobInfo
+	ConsoleHostID ID // The console host serving the process, if any
+	Services      []Service
+	Windows       []Window
+	Package       Package
+	Version       fileversion.Info
+	Signing       Signing
+	Hashes        ImageHashes
+	Errors        []CollectionError // Failures encountered during collection
+	Extensions    Extensions        // Information gathered by custom collectors
 }
 
 // Ref returns a reference to the running process that matches the process
 // ID of p.
 //
+// If p contains a creation time, the reference is opened with OpenUnique,
+// which returns ErrProcessReplaced if the process ID has since been reused
+// by another process. This can be accomplished by supplying the
+// CollectTimes option when collecting processes.
+//
 // It is the caller's responsibility to close the reference when finished
 // with it.
 func (p Process) Ref(rights ...processaccess.Rights) (*Ref, error) {
-	return Open(p.ID, rights...)
+	if p.Times.Creation.IsZero() {
+		return Open(p.ID, rights...)
+	}
+	return OpenUnique(p.UniqueID(), rights...)
+}
+
+// Err returns the first collection error that affected any of the
+// collectors in c, or nil if there were none.
+//
+// A failure to open the process affects all of the collectors that were
+// requested.
+func (p Process) Err(c Collector) error {
+	for _, err := range p.Errors {
+		if err.Collector&c != 0 {
+			return err
+		}
+	}
+	return nil
+}
+
+// fail records a collection error for p.
+func (p *Process) fail(c Collector, op string, err error) {
+	p.Errors = append(p.Errors, CollectionError{
+		Collector: c,
+		Op:        op,
+		Err:       err,
+	})
 }
 
 // UniqueID returns a unique identifier for the process by combining its
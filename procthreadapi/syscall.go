@@ -12,8 +12,9 @@ import (
 var (
 	modkernel32 = windows.NewLazySystemDLL("kernel32.dll")
 
-	procIsProcessCritical = modkernel32.NewProc("IsProcessCritical")
-	procTerminateProcess  = modkernel32.NewProc("TerminateProcess")
+	procIsProcessCritical     = modkernel32.NewProc("IsProcessCritical")
+	procQueryProcessCycleTime = modkernel32.NewProc("QueryProcessCycleTime")
+	procTerminateProcess      = modkernel32.NewProc("TerminateProcess")
 )
 
 // IsProcessCritical returns true if the given process handle represents
@@ -44,6 +45,34 @@ func IsProcessCritical(process syscall.Handle) (critical bool, err error) {
 	return
 }
 
+// QueryProcessCycleTime returns the number of CPU clock cycles used by the
+// threads of the given process. It calls the QueryProcessCycleTime windows
+// API function.
+//
+// This call is only supported on Windows Vista or newer.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/realtimeapiset/nf-realtimeapiset-queryprocesscycletime
+func QueryProcessCycleTime(process syscall.Handle) (cycles uint64, err error) {
+	if err := procQueryProcessCycleTime.Find(); err != nil {
+		return 0, err
+	}
+
+	r0, _, e := syscall.Syscall(
+		procQueryProcessCycleTime.Addr(),
+		2,
+		uintptr(process),
+		uintptr(unsafe.Pointer(&cycles)),
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
 // TerminateProcess attempts to terminate the process with the given handle.
 // It calls the TerminateProcess windows API function.
 //
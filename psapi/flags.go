@@ -13,3 +13,16 @@ const (
 
 	SnapAll = SnapHeapList | SnapModule | SnapProcess | SnapThread // TH32CS_SNAPALL
 )
+
+// Heap list flags.
+const (
+	HeapDefault = 0x00000001 // HF32_DEFAULT
+	HeapShared  = 0x00000002 // HF32_SHARED
+)
+
+// Heap block flags.
+const (
+	BlockFixed    = 0x00000001 // LF32_FIXED
+	BlockFree     = 0x00000002 // LF32_FREE
+	BlockMoveable = 0x00000004 // LF32_MOVEABLE
+)
@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package psapi
+
+import "syscall"
+
+// HeapEntry holds information about a block of memory within a heap.
+//
+// https://docs.microsoft.com/en-us/windows/desktop/api/tlhelp32/ns-tlhelp32-heapentry32
+type HeapEntry struct {
+	Size      uintptr
+	Handle    syscall.Handle
+	Address   uintptr
+	BlockSize uintptr
+	Flags     uint32
+	LockCount uint32 // Unused
+	Reserved  uint32 // Unused
+	ProcessID uint32
+	HeapID    uintptr
+}
+
+// Free returns true if the block is not in use.
+func (entry *HeapEntry) Free() bool {
+	return entry.Flags&BlockFree != 0
+}
@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package psapi
+
+// HeapListEntry holds information about a heap within a process.
+//
+// https://docs.microsoft.com/en-us/windows/desktop/api/tlhelp32/ns-tlhelp32-heaplist32
+type HeapListEntry struct {
+	Size      uintptr
+	ProcessID uint32
+	HeapID    uintptr
+	Flags     uint32
+}
+
+// Default returns true if the heap is the default heap of the process.
+func (entry *HeapListEntry) Default() bool {
+	return entry.Flags&HeapDefault != 0
+}
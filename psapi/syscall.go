@@ -16,8 +16,14 @@ var (
 	procCreateToolhelp32Snapshot = modkernel32.NewProc("CreateToolhelp32Snapshot")
 	procProcess32First           = modkernel32.NewProc("Process32FirstW")
 	procProcess32Next            = modkernel32.NewProc("Process32NextW")
+	procThread32First            = modkernel32.NewProc("Thread32First")
+	procThread32Next             = modkernel32.NewProc("Thread32Next")
 	procModule32First            = modkernel32.NewProc("Module32FirstW")
 	procModule32Next             = modkernel32.NewProc("Module32NextW")
+	procHeap32ListFirst          = modkernel32.NewProc("Heap32ListFirst")
+	procHeap32ListNext           = modkernel32.NewProc("Heap32ListNext")
+	procHeap32First              = modkernel32.NewProc("Heap32First")
+	procHeap32Next               = modkernel32.NewProc("Heap32Next")
 )
 
 // CreateSnapshot prepares a process, heap or module snapshot according to the
@@ -112,6 +118,66 @@ func NextProcess(snapshot syscall.Handle) (entry ProcessEntry, err error) {
 	return
 }
 
+// FirstThread returns the first thread entry from a snapshot.
+// It calls the Thread32First windows API function.
+//
+// FirstThread returns io.EOF if there are no threads in the snapshot.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/tlhelp32/nf-tlhelp32-thread32first
+func FirstThread(snapshot syscall.Handle) (entry ThreadEntry, err error) {
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	r0, _, e := syscall.Syscall(
+		procThread32First.Addr(),
+		2,
+		uintptr(snapshot),
+		uintptr(unsafe.Pointer(&entry)),
+		0)
+
+	if r0 == 0 {
+		switch e {
+		case 0:
+			err = syscall.EINVAL
+		case syscall.ERROR_NO_MORE_FILES:
+			err = io.EOF
+		default:
+			err = syscall.Errno(e)
+		}
+	}
+
+	return
+}
+
+// NextThread returns the next thread entry from a snapshot.
+// It calls the Thread32Next windows API function.
+//
+// NextThread returns io.EOF if there are no more threads in the snapshot.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/tlhelp32/nf-tlhelp32-thread32next
+func NextThread(snapshot syscall.Handle) (entry ThreadEntry, err error) {
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	r0, _, e := syscall.Syscall(
+		procThread32Next.Addr(),
+		2,
+		uintptr(snapshot),
+		uintptr(unsafe.Pointer(&entry)),
+		0)
+
+	if r0 == 0 {
+		switch e {
+		case 0:
+			err = syscall.EINVAL
+		case syscall.ERROR_NO_MORE_FILES:
+			err = io.EOF
+		default:
+			err = syscall.Errno(e)
+		}
+	}
+
+	return
+}
+
 // FirstModule returns the first module entry from a snapshot.
 // It calls the Module32FirstW windows API function.
 //
@@ -171,3 +237,132 @@ func NextModule(snapshot syscall.Handle) (entry ModuleEntry, err error) {
 
 	return
 }
+
+// FirstHeapList returns the first heap list entry from a snapshot.
+// It calls the Heap32ListFirst windows API function.
+//
+// The snapshot must have been created with the SnapHeapList flag.
+//
+// FirstHeapList returns io.EOF if there are no heaps in the snapshot.
+//
+// https://docs.microsoft.com/en-us/windows/desktop/api/tlhelp32/nf-tlhelp32-heap32listfirst
+func FirstHeapList(snapshot syscall.Handle) (entry HeapListEntry, err error) {
+	entry.Size = unsafe.Sizeof(entry)
+
+	r0, _, e := syscall.Syscall(
+		procHeap32ListFirst.Addr(),
+		2,
+		uintptr(snapshot),
+		uintptr(unsafe.Pointer(&entry)),
+		0)
+
+	if r0 == 0 {
+		switch e {
+		case 0:
+			err = syscall.EINVAL
+		case syscall.ERROR_NO_MORE_FILES:
+			err = io.EOF
+		default:
+			err = syscall.Errno(e)
+		}
+	}
+
+	return
+}
+
+// NextHeapList returns the next heap list entry from a snapshot.
+// It calls the Heap32ListNext windows API function.
+//
+// NextHeapList returns io.EOF if there are no more heaps in the snapshot.
+//
+// https://docs.microsoft.com/en-us/windows/desktop/api/tlhelp32/nf-tlhelp32-heap32listnext
+func NextHeapList(snapshot syscall.Handle) (entry HeapListEntry, err error) {
+	entry.Size = unsafe.Sizeof(entry)
+
+	r0, _, e := syscall.Syscall(
+		procHeap32ListNext.Addr(),
+		2,
+		uintptr(snapshot),
+		uintptr(unsafe.Pointer(&entry)),
+		0)
+
+	if r0 == 0 {
+		switch e {
+		case 0:
+			err = syscall.EINVAL
+		case syscall.ERROR_NO_MORE_FILES:
+			err = io.EOF
+		default:
+			err = syscall.Errno(e)
+		}
+	}
+
+	return
+}
+
+// FirstHeap returns the first block of the heap identified by the given
+// process ID and heap ID. It calls the Heap32First windows API function.
+//
+// Unlike the other snapshot functions, Heap32First walks the heap of a
+// live process. The heap ID is typically obtained from a HeapListEntry.
+//
+// FirstHeap returns io.EOF if there are no blocks in the heap.
+//
+// https://docs.microsoft.com/en-us/windows/desktop/api/tlhelp32/nf-tlhelp32-heap32first
+func FirstHeap(processID uint32, heapID uintptr) (entry HeapEntry, err error) {
+	entry.Size = unsafe.Sizeof(entry)
+
+	r0, _, e := syscall.Syscall(
+		procHeap32First.Addr(),
+		3,
+		uintptr(unsafe.Pointer(&entry)),
+		uintptr(processID),
+		heapID)
+
+	if r0 == 0 {
+		switch e {
+		case 0:
+			err = syscall.EINVAL
+		case syscall.ERROR_NO_MORE_FILES:
+			err = io.EOF
+		default:
+			err = syscall.Errno(e)
+		}
+	}
+
+	return
+}
+
+// NextHeap returns the block that follows entry within the same heap.
+// It calls the Heap32Next windows API function.
+//
+// The entry must have been returned by a previous call to FirstHeap or
+// NextHeap.
+//
+// NextHeap returns io.EOF if there are no more blocks in the heap.
+//
+// https://docs.microsoft.com/en-us/windows/desktop/api/tlhelp32/nf-tlhelp32-heap32next
+func NextHeap(entry HeapEntry) (next HeapEntry, err error) {
+	next = entry
+	next.Size = unsafe.Sizeof(next)
+
+	r0, _, e := syscall.Syscall(
+		procHeap32Next.Addr(),
+		1,
+		uintptr(unsafe.Pointer(&next)),
+		0,
+		0)
+
+	if r0 == 0 {
+		switch e {
+		case 0:
+			err = syscall.EINVAL
+		case syscall.ERROR_NO_MORE_FILES:
+			err = io.EOF
+		default:
+			err = syscall.Errno(e)
+		}
+	}
+
+	return
+}
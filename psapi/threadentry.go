@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package psapi
+
+// ThreadEntry holds information about a thread.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/tlhelp32/ns-tlhelp32-threadentry32
+type ThreadEntry struct {
+	Size           uint32
+	Usage          uint32 // Unused
+	ThreadID       uint32
+	OwnerProcessID uint32
+	BasePriority   int32
+	DeltaPriority  int32 // Unused
+	Flags          uint32
+}
@@ -7,9 +7,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"syscall"
 
+	"github.com/gentlemanautomaton/winproc/jobapi"
 	"github.com/gentlemanautomaton/winproc/nativeapi"
 	"github.com/gentlemanautomaton/winproc/processaccess"
 	"github.com/gentlemanautomaton/winproc/procthreadapi"
@@ -24,6 +26,8 @@ import (
 type Ref struct {
 	mutex  sync.RWMutex
 	handle syscall.Handle
+	pid    ID     // The process ID, if known
+	policy Policy // The safety policy for destructive operations
 }
 
 // Open returns a reference to the process with the given process ID and
@@ -32,6 +36,8 @@ type Ref struct {
 // If one or more access rights are provided, they will be combined. If no
 // access rights are provided, the QueryLimitedInformation right will be used.
 //
+// Use OpenDebug to retry with SeDebugPrivilege when access is denied.
+//
 // It is the caller's responsibility to close the handle when finished with it.
 func Open(pid ID, rights ...processaccess.Rights) (*Ref, error) {
 	var combinedRights processaccess.Rights
@@ -47,7 +53,37 @@ func Open(pid ID, rights ...processaccess.Rights) (*Ref, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Ref{handle: handle}, nil
+	return &Ref{handle: handle, pid: pid}, nil
+}
+
+// OpenUnique returns a reference to the process with the given unique ID
+// and access rights. It verifies that the process with the ID was created
+// at the expected time, which guards against the ID having been reused by
+// another process.
+//
+// If the process has exited and its ID has been reused, it returns
+// ErrProcessReplaced. The QueryLimitedInformation right is always included,
+// because it is needed to verify the creation time.
+//
+// It is the caller's responsibility to close the handle when finished with it.
+func OpenUnique(uid UniqueID, rights ...processaccess.Rights) (*Ref, error) {
+	rights = append(rights[:len(rights):len(rights)], processaccess.QueryLimitedInformation)
+	ref, err := Open(uid.ID, rights...)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := ref.UniqueID()
+	if err != nil {
+		ref.Close()
+		return nil, err
+	}
+	if actual != uid {
+		ref.Close()
+		return nil, ErrProcessReplaced
+	}
+
+	return ref, nil
 }
 
 // ID returns the ID of the process.
@@ -66,6 +102,33 @@ func (ref *Ref) ID() (ID, error) {
 	return ID(id), nil
 }
 
+// processID returns the ID of the process, even if ref was not opened with
+// query rights.
+func (ref *Ref) processID() (ID, error) {
+	if ref.pid != 0 {
+		return ref.pid, nil
+	}
+	return ref.ID()
+}
+
+// SetPolicy sets the safety policy that governs destructive operations on
+// the process, such as Terminate and Suspend. By default the zero value of
+// Policy is used.
+func (ref *Ref) SetPolicy(policy Policy) {
+	ref.mutex.Lock()
+	defer ref.mutex.Unlock()
+	ref.policy = policy
+}
+
+// checkPolicy returns an error if the safety policy of ref forbids
+// destructive operations on the process.
+func (ref *Ref) checkPolicy() error {
+	ref.mutex.RLock()
+	policy := ref.policy
+	ref.mutex.RUnlock()
+	return policy.CheckRef(ref)
+}
+
 // UniqueID returns a unique identifier for the process by combining its
 // creation time and process ID.
 func (ref *Ref) UniqueID() (UniqueID, error) {
@@ -92,6 +155,135 @@ func (ref *Ref) UniqueID() (UniqueID, error) {
 	}, nil
 }
 
+// ParentID returns the ID of the process that created the process.
+//
+// The parent may have exited since, in which case its ID may have been
+// reused by another process. Use OpenParent to obtain a verified
+// reference to the parent.
+func (ref *Ref) ParentID() (ID, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return 0, ErrClosed
+	}
+
+	info, err := nativeapi.ProcessBasicInfo(ref.handle)
+	if err != nil {
+		return 0, err
+	}
+	return ID(info.InheritedFromUniqueProcessID), nil
+}
+
+// OpenParent returns a reference to the process that created the process,
+// with the given access rights.
+//
+// It verifies that the parent was created before the process, which guards
+// against the parent ID having been reused by another process. If the
+// parent has exited and its ID has been reused, it returns
+// ErrProcessReplaced. The QueryLimitedInformation right is always
+// included, because it is needed to verify the creation time.
+//
+// It is the caller's responsibility to close the handle when finished with it.
+func (ref *Ref) OpenParent(rights ...processaccess.Rights) (*Ref, error) {
+	pid, err := ref.ParentID()
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := ref.Times()
+	if err != nil {
+		return nil, err
+	}
+
+	rights = append(rights[:len(rights):len(rights)], processaccess.QueryLimitedInformation)
+	parent, err := Open(pid, rights...)
+	if err != nil {
+		return nil, err
+	}
+
+	parentTimes, err := parent.Times()
+	if err != nil {
+		parent.Close()
+		return nil, err
+	}
+	if !parentTimes.Creation.Before(times.Creation) {
+		parent.Close()
+		return nil, ErrProcessReplaced
+	}
+
+	return parent, nil
+}
+
+// Ancestors returns the chain of processes that led to the creation of the
+// process, starting with its parent. Each ancestor is verified to have been
+// created before its child.
+//
+// The chain ends at the first ancestor that has exited or whose process ID
+// has been reused. If an ancestor can't be opened for another reason, the
+// chain collected so far is returned along with the error.
+//
+// Each process includes its ID, parent ID, name, image path and times.
+func (ref *Ref) Ancestors() ([]Process, error) {
+	var (
+		ancestors []Process
+		seen      = make(map[ID]bool)
+		child     = ref
+	)
+
+	defer func() {
+		if child != ref {
+			child.Close()
+		}
+	}()
+
+	for {
+		// The kernel processes can't be opened
+		if pid, err := child.ParentID(); err != nil {
+			return ancestors, err
+		} else if kernelProcess(pid) || seen[pid] {
+			return ancestors, nil
+		}
+
+		parent, err := child.OpenParent()
+		if err != nil {
+			if errorKind(err) == ErrProcessGone {
+				return ancestors, nil
+			}
+			return ancestors, err
+		}
+		if child != ref {
+			child.Close()
+		}
+		child = parent
+
+		proc, err := parent.describe()
+		if err != nil {
+			return ancestors, err
+		}
+		seen[proc.ID] = true
+		ancestors = append(ancestors, proc)
+	}
+}
+
+// describe returns a process that describes ref.
+func (ref *Ref) describe() (proc Process, err error) {
+	if proc.ID, err = ref.ID(); err != nil {
+		return Process{}, err
+	}
+	if proc.ParentID, err = ref.ParentID(); err != nil {
+		return Process{}, err
+	}
+	if proc.Times, err = ref.Times(); err != nil {
+		return Process{}, err
+	}
+	if path, err := ref.ImagePath(); err == nil {
+		proc.ImagePath = path
+		proc.Name = filepath.Base(path)
+	}
+	return proc, nil
+}
+
 // CommandLine returns the command line used to invoke the process.
 //
 // This call is only supported on Windows 10 1511 or newer.
@@ -112,6 +304,30 @@ func (ref *Ref) CommandLine() (command string, err error) {
 	return nativeapi.ProcessCommandLine(ref.handle)
 }
 
+// ImagePath returns the full path of the executable image for the process.
+func (ref *Ref) ImagePath() (path string, err error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return "", ErrClosed
+	}
+
+	for _, length := range []int{windows.MAX_PATH, windows.MAX_LONG_PATH} {
+		buffer := make([]uint16, length)
+		size := uint32(len(buffer))
+		err = windows.QueryFullProcessImageName(windows.Handle(ref.handle), 0, &buffer[0], &size)
+		switch err {
+		case nil:
+			return windows.UTF16ToString(buffer[:size]), nil
+		case windows.ERROR_INSUFFICIENT_BUFFER:
+		default:
+			return "", err
+		}
+	}
+	return "", err
+}
+
 // SessionID returns the ID of the windows session associated with the
 // process.
 func (ref *Ref) SessionID() (sessionID uint32, err error) {
@@ -160,6 +376,53 @@ func (ref *Ref) Times() (times Times, err error) {
 	}, nil
 }
 
+// ConsoleHostID returns the ID of the console host process that serves
+// the process, such as conhost.exe or OpenConsole.exe.
+//
+// It returns zero if the process is not attached to a console.
+func (ref *Ref) ConsoleHostID() (ID, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return 0, ErrClosed
+	}
+
+	id, err := nativeapi.ProcessConsoleHost(ref.handle)
+	if err != nil {
+		return 0, err
+	}
+	return ID(id), nil
+}
+
+// Package returns the package identity of the process. It returns a zero
+// value if the process does not belong to a package.
+//
+// This call is only supported on Windows 8 or newer.
+func (ref *Ref) Package() (Package, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return Package{}, ErrClosed
+	}
+
+	return packageFromProcess(ref.handle)
+}
+
+// CycleTime returns the number of CPU clock cycles used by all threads of
+// the process, including threads that have exited.
+func (ref *Ref) CycleTime() (cycles uint64, err error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return 0, ErrClosed
+	}
+
+	return procthreadapi.QueryProcessCycleTime(ref.handle)
+}
+
 // Critical returns true if the process is considered critical to the system's
 // operation.
 //
@@ -175,10 +438,93 @@ func (ref *Ref) Critical() (bool, error) {
 	return procthreadapi.IsProcessCritical(ref.handle)
 }
 
+// InJob returns true if the process is a member of a job object.
+func (ref *Ref) InJob() (bool, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return false, ErrClosed
+	}
+
+	return jobapi.IsProcessInJob(ref.handle, 0)
+}
+
+// MemberOf returns true if the process is a member of the job object with
+// the given handle.
+func (ref *Ref) MemberOf(job syscall.Handle) (bool, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return false, ErrClosed
+	}
+
+	return jobapi.IsProcessInJob(ref.handle, job)
+}
+
+// AssignToJob assigns the process to the job object with the given handle.
+//
+// The reference must have been opened with the SetQuota and Terminate
+// access rights.
+func (ref *Ref) AssignToJob(job syscall.Handle) error {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	return jobapi.AssignProcess(job, ref.handle)
+}
+
+// JobMemory returns commit usage and limit information for the job object
+// that the process belongs to.
+//
+// This call is only supported on Windows 10 or newer.
+func (ref *Ref) JobMemory() (JobMemory, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return JobMemory{}, ErrClosed
+	}
+
+	info, err := nativeapi.ProcessJobMemory(ref.handle)
+	if err != nil {
+		return JobMemory{}, err
+	}
+	return jobMemoryFromInfo(info), nil
+}
+
+// HeapSummary walks the heaps of the process and returns a summary of
+// their allocations.
+//
+// This call can take a long time to complete for processes with large
+// heaps.
+func (ref *Ref) HeapSummary() (HeapSummary, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return HeapSummary{}, ErrClosed
+	}
+
+	id, err := windows.GetProcessId(windows.Handle(ref.handle))
+	if err != nil {
+		return HeapSummary{}, err
+	}
+
+	return heapSummary(id)
+}
+
 // Wait waits until the process terminates or ctx is cancelled. It
 // returns nil if the process has terminated.
 //
 // If ctx is cancelled the value of ctx.Err() will be returned.
+//
+// Each call blocks an operating system thread. Use a Waiter to wait for
+// many processes at once.
 func (ref *Ref) Wait(ctx context.Context) error {
 	// Hold a read lock for the duration of the call. This will block
 	// calls to ref.Close() until we're done.
@@ -250,9 +596,89 @@ func (ref *Ref) Wait(ctx context.Context) error {
 	}
 }
 
+// Priority returns the priority class of the process.
+func (ref *Ref) Priority() (PriorityClass, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return 0, ErrClosed
+	}
+
+	class, err := windows.GetPriorityClass(windows.Handle(ref.handle))
+	return PriorityClass(class), err
+}
+
+// SetPriority sets the priority class of the process.
+//
+// The reference must have been opened with the SetInformation access right.
+func (ref *Ref) SetPriority(class PriorityClass) error {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	return windows.SetPriorityClass(windows.Handle(ref.handle), uint32(class))
+}
+
+// Suspend suspends all of the threads in the process. Each call increments
+// the suspend count of every thread, so it must be balanced by a call to
+// Resume.
+//
+// The reference must have been opened with the SuspendResume access right.
+// The operation is subject to the safety policy of ref.
+func (ref *Ref) Suspend() error {
+	if err := ref.checkPolicy(); err != nil {
+		return err
+	}
+
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	return nativeapi.SuspendProcess(ref.handle)
+}
+
+// Resume resumes all of the threads in the process that were suspended by
+// a previous call to Suspend.
+//
+// The reference must have been opened with the SuspendResume access right.
+func (ref *Ref) Resume() error {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return ErrClosed
+	}
+
+	return nativeapi.ResumeProcess(ref.handle)
+}
+
+// Suspended returns true if every thread in the process is suspended.
+//
+// This call is only supported on Windows 8.1 or newer.
+func (ref *Ref) Suspended() (bool, error) {
+	pid, err := ref.ID()
+	if err != nil {
+		return false, err
+	}
+
+	return threadsSuspended(pid)
+}
+
 // Terminate instructs the operating system to terminate the process with the
 // given exit code.
+//
+// The operation is subject to the safety policy of ref.
 func (ref *Ref) Terminate(exitCode uint32) error {
+	if err := ref.checkPolicy(); err != nil {
+		return err
+	}
 	return procthreadapi.TerminateProcess(ref.handle, exitCode)
 }
 
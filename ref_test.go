@@ -6,6 +6,7 @@ package winproc_test
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"testing"
 	"time"
@@ -70,3 +71,114 @@ func TestWaitCancellation(t *testing.T) {
 		})
 	}
 }
+
+func TestSuspendResume(t *testing.T) {
+	command := exec.Command("notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	ref, err := winproc.Open(winproc.ID(command.Process.Pid), processaccess.SuspendResume, processaccess.QueryLimitedInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if err := ref.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	if suspended, err := ref.Suspended(); err != nil {
+		t.Error(err)
+	} else if !suspended {
+		t.Errorf("the process was not suspended")
+	}
+
+	if err := ref.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if suspended, err := ref.Suspended(); err != nil {
+		t.Error(err)
+	} else if suspended {
+		t.Errorf("the process was not resumed")
+	}
+}
+
+func TestOpenUnique(t *testing.T) {
+	self, err := winproc.Open(winproc.ID(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer self.Close()
+
+	uid, err := self.UniqueID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := winproc.OpenUnique(uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref.Close()
+
+	uid.Creation--
+	if _, err := winproc.OpenUnique(uid); err != winproc.ErrProcessReplaced {
+		t.Fatalf("expected %v, got %v", winproc.ErrProcessReplaced, err)
+	}
+}
+
+func TestParent(t *testing.T) {
+	command := exec.Command("notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	ref, err := winproc.Open(winproc.ID(command.Process.Pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	self := winproc.ID(os.Getpid())
+
+	if pid, err := ref.ParentID(); err != nil {
+		t.Fatal(err)
+	} else if pid != self {
+		t.Fatalf("expected parent ID %d, got %d", self, pid)
+	}
+
+	parent, err := ref.OpenParent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer parent.Close()
+	if pid, err := parent.ID(); err != nil {
+		t.Fatal(err)
+	} else if pid != self {
+		t.Fatalf("expected parent reference to PID %d, got %d", self, pid)
+	}
+
+	ancestors, err := ref.Ancestors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ancestors) == 0 || ancestors[0].ID != self {
+		t.Fatalf("expected the first ancestor to be PID %d, got %v", self, ancestors)
+	}
+}
+
+func TestOpenDebug(t *testing.T) {
+	ref, err := winproc.OpenDebug(winproc.ID(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if pid, err := ref.ID(); err != nil {
+		t.Fatal(err)
+	} else if pid != winproc.ID(os.Getpid()) {
+		t.Fatalf("expected PID %d, got %d", os.Getpid(), pid)
+	}
+}
@@ -0,0 +1,189 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Service holds information about a windows service hosted by a process.
+type Service struct {
+	Name        string
+	DisplayName string
+	State       ServiceState
+}
+
+// String returns a string representation of the service.
+func (s Service) String() string {
+	return s.Name
+}
+
+// ServiceState is the current state of a windows service.
+type ServiceState uint32
+
+// Windows service states.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winsvc/ns-winsvc-service_status_process
+const (
+	ServiceStopped         ServiceState = 1 // SERVICE_STOPPED
+	ServiceStartPending    ServiceState = 2 // SERVICE_START_PENDING
+	ServiceStopPending     ServiceState = 3 // SERVICE_STOP_PENDING
+	ServiceRunning         ServiceState = 4 // SERVICE_RUNNING
+	ServiceContinuePending ServiceState = 5 // SERVICE_CONTINUE_PENDING
+	ServicePausePending    ServiceState = 6 // SERVICE_PAUSE_PENDING
+	ServicePaused          ServiceState = 7 // SERVICE_PAUSED
+)
+
+// String returns a string representation of the service state.
+func (state ServiceState) String() string {
+	switch state {
+	case ServiceStopped:
+		return "stopped"
+	case ServiceStartPending:
+		return "start pending"
+	case ServiceStopPending:
+		return "stop pending"
+	case ServiceRunning:
+		return "running"
+	case ServiceContinuePending:
+		return "continue pending"
+	case ServicePausePending:
+		return "pause pending"
+	case ServicePaused:
+		return "paused"
+	default:
+		return "ServiceState(" + strconv.Itoa(int(state)) + ")"
+	}
+}
+
+// ServiceEntry is an entry in a table of services. It identifies the
+// process that hosts a service.
+type ServiceEntry struct {
+	Service
+	ProcessID ID // Zero if the service isn't running
+}
+
+// A ServiceSource provides a table of the services known to the system.
+type ServiceSource interface {
+	Services() ([]ServiceEntry, error)
+}
+
+// ServiceCollector is a collection option that attaches services to the
+// processes that host them. If the table of services can't be retrieved,
+// the failure is recorded in the Errors field of each process and
+// attributed to CollectServices.
+type ServiceCollector struct {
+	// Source provides the table of services. If it is nil the local
+	// Service Control Manager is queried.
+	Source ServiceSource
+}
+
+// Apply applies the service collector to the collection.
+func (c ServiceCollector) Apply(col *Collection) {
+	source := c.Source
+	if source == nil {
+		source = ServiceManager{}
+	}
+
+	entries, err := source.Services()
+	if err != nil {
+		for i := range col.Procs {
+			if !col.Excluded[i] {
+				col.Procs[i].fail(CollectServices, "services", err)
+			}
+		}
+		return
+	}
+
+	hosted := make(map[ID][]Service)
+	for _, entry := range entries {
+		if entry.ProcessID == 0 {
+			continue
+		}
+		hosted[entry.ProcessID] = append(hosted[entry.ProcessID], entry.Service)
+	}
+
+	for i := range col.Procs {
+		if col.Excluded[i] {
+			continue
+		}
+		services := hosted[col.Procs[i].ID]
+		if len(services) == 0 {
+			continue
+		}
+		sort.Slice(services, func(a, b int) bool {
+			return services[a].Name < services[b].Name
+		})
+		col.Procs[i].Services = services
+	}
+}
+
+// ServiceManager is a ServiceSource that queries the local Service Control
+// Manager.
+type ServiceManager struct{}
+
+// Services returns a table of the win32 services known to the local
+// Service Control Manager. It calls the EnumServicesStatusEx windows API
+// function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winsvc/nf-winsvc-enumservicesstatusexw
+func (ServiceManager) Services() (entries []ServiceEntry, err error) {
+	mgr, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_ENUMERATE_SERVICE)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseServiceHandle(mgr)
+
+	var (
+		buffer   = make([]byte, 64*1024)
+		needed   uint32
+		returned uint32
+		resume   uint32
+	)
+
+	for {
+		err = windows.EnumServicesStatusEx(
+			mgr,
+			windows.SC_ENUM_PROCESS_INFO,
+			windows.SERVICE_WIN32,
+			windows.SERVICE_STATE_ALL,
+			&buffer[0],
+			uint32(len(buffer)),
+			&needed,
+			&returned,
+			&resume,
+			nil)
+		if err != nil && err != windows.ERROR_MORE_DATA {
+			return nil, err
+		}
+
+		if returned > 0 {
+			statuses := unsafe.Slice((*windows.ENUM_SERVICE_STATUS_PROCESS)(unsafe.Pointer(&buffer[0])), returned)
+			for _, status := range statuses {
+				entries = append(entries, ServiceEntry{
+					Service: Service{
+						Name:        windows.UTF16PtrToString(status.ServiceName),
+						DisplayName: windows.UTF16PtrToString(status.DisplayName),
+						State:       ServiceState(status.ServiceStatusProcess.CurrentState),
+					},
+					ProcessID: ID(status.ServiceStatusProcess.ProcessId),
+				})
+			}
+		}
+
+		if err == nil {
+			return entries, nil
+		}
+
+		// Continue from the resume handle, growing the buffer if even a
+		// single entry wouldn't fit
+		if int(needed) > len(buffer) {
+			buffer = make([]byte, needed)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+type fakeServiceTable []winproc.ServiceEntry
+
+func (table fakeServiceTable) Services() ([]winproc.ServiceEntry, error) {
+	return table, nil
+}
+
+type failingServiceTable struct{}
+
+func (failingServiceTable) Services() ([]winproc.ServiceEntry, error) {
+	return nil, errors.New("access denied")
+}
+
+func TestServiceCollector(t *testing.T) {
+	table := fakeServiceTable{
+		{Service: winproc.Service{Name: "LanmanWorkstation", State: winproc.ServiceRunning}, ProcessID: 20},
+		{Service: winproc.Service{Name: "Dnscache", State: winproc.ServiceRunning}, ProcessID: 20},
+		{Service: winproc.Service{Name: "Spooler", State: winproc.ServiceRunning}, ProcessID: 30},
+		{Service: winproc.Service{Name: "Fax", State: winproc.ServiceStopped}, ProcessID: 0},
+	}
+
+	col := winproc.Collection{
+		Procs: []winproc.Process{
+			{ID: 10, Name: "wininit.exe"},
+			{ID: 20, Name: "svchost.exe"},
+			{ID: 30, Name: "spoolsv.exe"},
+		},
+		Excluded: []bool{false, false, true},
+	}
+
+	winproc.ServiceCollector{Source: table}.Apply(&col)
+
+	if services := col.Procs[0].Services; len(services) != 0 {
+		t.Errorf("wininit.exe: unexpected services: %v", services)
+	}
+
+	if services := col.Procs[1].Services; len(services) != 2 {
+		t.Errorf("svchost.exe: expected 2 services, got %v", services)
+	} else if services[0].Name != "Dnscache" || services[1].Name != "LanmanWorkstation" {
+		t.Errorf("svchost.exe: unexpected services: %v", services)
+	}
+
+	if services := col.Procs[2].Services; len(services) != 0 {
+		t.Errorf("spoolsv.exe: services were collected for an excluded process: %v", services)
+	}
+
+	match := winproc.MatchService(func(name string) bool { return name == "Dnscache" })
+	if !match(col.Procs[1]) {
+		t.Errorf("MatchService did not match svchost.exe")
+	}
+	if match(col.Procs[0]) {
+		t.Errorf("MatchService matched wininit.exe")
+	}
+}
+
+func TestServiceCollectorError(t *testing.T) {
+	col := winproc.Collection{
+		Procs:    []winproc.Process{{ID: 20, Name: "svchost.exe"}},
+		Excluded: []bool{false},
+	}
+
+	winproc.ServiceCollector{Source: failingServiceTable{}}.Apply(&col)
+
+	if services := col.Procs[0].Services; len(services) != 0 {
+		t.Errorf("unexpected services: %v", services)
+	}
+	if err := col.Procs[0].Err(winproc.CollectServices); err == nil {
+		t.Errorf("the failure was not recorded")
+	}
+}
@@ -0,0 +1,95 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc/authenticode"
+)
+
+// SignatureStatus describes the state of the Authenticode signature of a
+// process image.
+type SignatureStatus int
+
+// Signature states.
+const (
+	// SignatureUnknown indicates that the signature was not examined or
+	// could not be read.
+	SignatureUnknown SignatureStatus = iota
+
+	// SignatureNotEmbedded indicates that the image does not contain an
+	// embedded signature. Images that are signed through a security
+	// catalog are reported this way.
+	SignatureNotEmbedded
+
+	// SignatureValid indicates that the image has an embedded signature
+	// that covers the image and was made by its signer.
+	SignatureValid
+
+	// SignatureInvalid indicates that the image has an embedded signature
+	// that does not match the image or could not be verified.
+	SignatureInvalid
+)
+
+// String returns a string representation of the status.
+func (status SignatureStatus) String() string {
+	switch status {
+	case SignatureUnknown:
+		return "unknown"
+	case SignatureNotEmbedded:
+		return "not embedded"
+	case SignatureValid:
+		return "valid"
+	case SignatureInvalid:
+		return "invalid"
+	default:
+		return "SignatureStatus(" + strconv.Itoa(int(status)) + ")"
+	}
+}
+
+// Signing holds information about the Authenticode signature of a process
+// image.
+//
+// The signer's certificate chain is not checked against a set of trusted
+// roots.
+type Signing struct {
+	Status     SignatureStatus
+	Subject    string
+	Issuer     string
+	Thumbprint string
+	Timestamp  time.Time
+}
+
+// signatures is shared by all collections so that each image file is only
+// examined once.
+var signatures = newImageCache[Signing](4096)
+
+// readSigning examines the signature of the image file at path.
+func readSigning(path string) (Signing, error) {
+	sig, err := authenticode.Read(path)
+	switch {
+	case err == authenticode.ErrNotSigned:
+		return Signing{Status: SignatureNotEmbedded}, nil
+	case errors.Is(err, authenticode.ErrMalformed), err == authenticode.ErrUnsupportedAlgorithm:
+		return Signing{Status: SignatureInvalid}, nil
+	case err != nil:
+		return Signing{}, err
+	}
+
+	status := SignatureInvalid
+	if sig.Valid() {
+		status = SignatureValid
+	}
+
+	return Signing{
+		Status:     status,
+		Subject:    sig.Subject,
+		Issuer:     sig.Issuer,
+		Thumbprint: sig.Thumbprint,
+		Timestamp:  sig.Timestamp,
+	}, nil
+}
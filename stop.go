@@ -0,0 +1,195 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc/winuser"
+	"golang.org/x/sys/windows"
+)
+
+// StopStage identifies a stage of a graceful stop.
+type StopStage int
+
+// Graceful stop stages.
+const (
+	// StopNone indicates that the process was not stopped.
+	StopNone StopStage = iota
+
+	// StopExited indicates that the process had already exited.
+	StopExited
+
+	// StopClose asks the process to exit by posting WM_CLOSE to each of its
+	// top-level windows.
+	StopClose
+
+	// StopBreak asks the process to exit by sending CTRL+BREAK to the
+	// console process group that it leads.
+	//
+	// This only works if the process shares the caller's console and was
+	// created with the CREATE_NEW_PROCESS_GROUP flag. If it wasn't, Windows
+	// may deliver the signal to every process attached to the caller's
+	// console, including the caller. For this reason it is not one of the
+	// default stages.
+	//
+	// CTRL+C cannot be directed at a particular process group, so it is
+	// not offered.
+	StopBreak
+
+	// StopTerminate forcibly terminates the process with exit code 1.
+	StopTerminate
+)
+
+// String returns a string representation of the stop stage.
+func (s StopStage) String() string {
+	switch s {
+	case StopNone:
+		return "none"
+	case StopExited:
+		return "exited"
+	case StopClose:
+		return "close"
+	case StopBreak:
+		return "break"
+	case StopTerminate:
+		return "terminate"
+	default:
+		return "unknown"
+	}
+}
+
+// Stop attempts to stop the process gracefully. Each of the given stages
+// is attempted in order, waiting up to timeout for the process to exit
+// after each one. If no stages are provided, StopClose and StopTerminate
+// will be used.
+//
+// Stop returns the stage that caused the process to exit, or StopExited
+// if it had already exited. A process that exits just after a stage times
+// out is attributed to that stage. If the process is still running after
+// every stage has been attempted it returns StopNone and
+// ErrProcessStillActive.
+// If ctx is cancelled it returns StopNone and ctx.Err().
+//
+// The reference must have been opened with the QueryLimitedInformation
+// and Synchronize access rights, as well as the Terminate right if
+// StopTerminate is used. The operation is subject to the safety policy of
+// ref.
+func (ref *Ref) Stop(ctx context.Context, timeout time.Duration, stages ...StopStage) (StopStage, error) {
+	if len(stages) == 0 {
+		stages = []StopStage{StopClose, StopTerminate}
+	}
+
+	if err := ref.checkPolicy(); err != nil {
+		return StopNone, err
+	}
+
+	exited, err := ref.exited()
+	if err != nil {
+		return StopNone, err
+	}
+	if exited {
+		return StopExited, nil
+	}
+
+	pid, err := ref.ID()
+	if err != nil {
+		return StopNone, err
+	}
+
+	attempted := StopNone
+	for _, stage := range stages {
+		switch stage {
+		case StopClose:
+			if closeWindows(pid) == 0 {
+				continue
+			}
+		case StopBreak:
+			if err := windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(pid)); err != nil {
+				continue
+			}
+		case StopTerminate:
+			if err := ref.Terminate(1); err != nil {
+				return ref.stageFailed(attempted, err)
+			}
+		default:
+			continue
+		}
+
+		switch err := ref.waitFor(ctx, timeout); err {
+		case nil:
+			return stage, nil
+		case context.DeadlineExceeded:
+			if ctx.Err() != nil {
+				return StopNone, err
+			}
+			attempted = stage
+		default:
+			return StopNone, err
+		}
+	}
+
+	return StopNone, ErrProcessStillActive
+}
+
+// stageFailed is called when a stop stage fails with err. The process may
+// have exited after the previous stage timed out, in which case the stage
+// cannot be carried out but the process did stop. If the process has
+// exited it returns the last stage that was attempted, or StopExited if no
+// stage was attempted, and a nil error. Otherwise it returns StopNone and
+// err.
+func (ref *Ref) stageFailed(attempted StopStage, err error) (StopStage, error) {
+	if exited, _ := ref.exited(); !exited {
+		return StopNone, err
+	}
+	if attempted == StopNone {
+		return StopExited, nil
+	}
+	return attempted, nil
+}
+
+// exited returns true if the process has exited.
+func (ref *Ref) exited() (bool, error) {
+	ref.mutex.RLock()
+	defer ref.mutex.RUnlock()
+
+	if ref.handle == syscall.InvalidHandle {
+		return false, ErrClosed
+	}
+
+	event, err := windows.WaitForSingleObject(windows.Handle(ref.handle), 0)
+	if err != nil {
+		return false, err
+	}
+	return event == windows.WAIT_OBJECT_0, nil
+}
+
+// waitFor waits up to timeout for the process to exit.
+func (ref *Ref) waitFor(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return ref.Wait(ctx)
+}
+
+// closeWindows posts WM_CLOSE to each top-level window belonging to the
+// process with the given process ID. It returns the number of windows
+// that the message was posted to.
+func closeWindows(pid ID) (posted int) {
+	var hwnds []winuser.HWND
+	winuser.EnumWindows(func(hwnd winuser.HWND) bool {
+		if _, owner, err := winuser.WindowThreadProcessID(hwnd); err == nil && ID(owner) == pid {
+			hwnds = append(hwnds, hwnd)
+		}
+		return true
+	})
+
+	for _, hwnd := range hwnds {
+		if winuser.PostMessage(hwnd, winuser.MessageClose, 0, 0) == nil {
+			posted++
+		}
+	}
+	return posted
+}
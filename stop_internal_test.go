@@ -0,0 +1,79 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+func TestStopStageFailedAfterExit(t *testing.T) {
+	command := exec.Command("cmd", "/c", "exit 0")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Wait()
+
+	ref, err := Open(ID(command.Process.Pid), processaccess.QueryLimitedInformation, processaccess.Synchronize, processaccess.Terminate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if err := ref.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Terminating a process that has already exited typically fails
+	termErr := ref.Terminate(1)
+	if termErr == nil {
+		termErr = errors.New("terminate failed")
+	}
+
+	tests := []struct {
+		Attempted StopStage
+		Want      StopStage
+	}{
+		{StopNone, StopExited},
+		{StopClose, StopClose},
+		{StopBreak, StopBreak},
+	}
+	for _, test := range tests {
+		stage, err := ref.stageFailed(test.Attempted, termErr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.Attempted, err)
+		}
+		if stage != test.Want {
+			t.Errorf("%s: stage is %s, want %s", test.Attempted, stage, test.Want)
+		}
+	}
+}
+
+func TestStopStageFailedWhileRunning(t *testing.T) {
+	command := exec.Command("ping", "-n", "30", "127.0.0.1")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Wait()
+	defer command.Process.Kill()
+
+	ref, err := Open(ID(command.Process.Pid), processaccess.QueryLimitedInformation, processaccess.Synchronize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	failure := errors.New("terminate failed")
+	stage, err := ref.stageFailed(StopClose, failure)
+	if err != failure {
+		t.Errorf("error is %v, want %v", err, failure)
+	}
+	if stage != StopNone {
+		t.Errorf("stage is %s, want %s", stage, StopNone)
+	}
+}
@@ -0,0 +1,185 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+	"github.com/gentlemanautomaton/winproc/winuser"
+	"golang.org/x/sys/windows"
+)
+
+// windowHelperEnv is set in the environment of a test process that should
+// run as a window helper.
+const windowHelperEnv = "WINPROC_TEST_WINDOW_HELPER"
+
+var (
+	moduser32 = windows.NewLazySystemDLL("user32.dll")
+
+	procRegisterClassExW = moduser32.NewProc("RegisterClassExW")
+	procCreateWindowExW  = moduser32.NewProc("CreateWindowExW")
+	procDefWindowProcW   = moduser32.NewProc("DefWindowProcW")
+	procGetMessageW      = moduser32.NewProc("GetMessageW")
+	procDispatchMessageW = moduser32.NewProc("DispatchMessageW")
+	procPostQuitMessage  = moduser32.NewProc("PostQuitMessage")
+)
+
+// wndClassEx is a WNDCLASSEXW structure.
+type wndClassEx struct {
+	Size       uint32
+	Style      uint32
+	WndProc    uintptr
+	ClsExtra   int32
+	WndExtra   int32
+	Instance   syscall.Handle
+	Icon       syscall.Handle
+	Cursor     syscall.Handle
+	Background syscall.Handle
+	MenuName   *uint16
+	ClassName  *uint16
+	IconSm     syscall.Handle
+}
+
+// msg is a MSG structure.
+type msg struct {
+	HWND    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	X, Y    int32
+	Private uint32
+}
+
+// TestWindowHelper is not a real test. It runs a window that closes when
+// it receives WM_CLOSE, for use by tests that need a process which exits
+// gracefully.
+func TestWindowHelper(t *testing.T) {
+	if os.Getenv(windowHelperEnv) != "1" {
+		t.Skip("only runs as a helper process")
+	}
+
+	runtime.LockOSThread()
+
+	const wmDestroy = 0x0002
+	wndProc := syscall.NewCallback(func(hwnd, message, wParam, lParam uintptr) uintptr {
+		if message == wmDestroy {
+			procPostQuitMessage.Call(0)
+			return 0
+		}
+		r0, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+		return r0
+	})
+
+	className, _ := syscall.UTF16PtrFromString("WinprocTestWindow")
+	class := wndClassEx{WndProc: wndProc, ClassName: className}
+	class.Size = uint32(unsafe.Sizeof(class))
+	if r0, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&class))); r0 == 0 {
+		t.Fatal(err)
+	}
+
+	const wsOverlappedWindow = 0x00CF0000
+	if r0, _, err := procCreateWindowExW.Call(0, uintptr(unsafe.Pointer(className)), uintptr(unsafe.Pointer(className)), wsOverlappedWindow, 0, 0, 100, 100, 0, 0, 0, 0); r0 == 0 {
+		t.Fatal(err)
+	}
+
+	var m msg
+	for {
+		r0, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(r0) <= 0 {
+			break
+		}
+		procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+	}
+	os.Exit(0)
+}
+
+// startWindowHelper starts a copy of the test binary that runs
+// TestWindowHelper, and waits for its window to appear.
+func startWindowHelper(t *testing.T) *exec.Cmd {
+	t.Helper()
+
+	command := exec.Command(os.Args[0], "-test.run=^TestWindowHelper$")
+	command.Env = append(os.Environ(), windowHelperEnv+"=1")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { command.Process.Kill() })
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		found := false
+		winuser.EnumWindows(func(hwnd winuser.HWND) bool {
+			if _, pid, err := winuser.WindowThreadProcessID(hwnd); err == nil && int(pid) == command.Process.Pid {
+				found = true
+				return false
+			}
+			return true
+		})
+		if found {
+			return command
+		}
+	}
+	t.Fatal("the helper window did not appear")
+	return nil
+}
+
+func TestStopClose(t *testing.T) {
+	command := startWindowHelper(t)
+
+	ref, err := winproc.FromCmd(command, processaccess.QueryLimitedInformation, processaccess.Synchronize, processaccess.Terminate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	stage, err := ref.Stop(context.Background(), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage != winproc.StopClose {
+		t.Fatalf("expected stop stage %s, got %s", winproc.StopClose, stage)
+	}
+
+	if status, err := ref.ExitStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Code != 0 {
+		t.Fatalf("the helper exited with code %d instead of closing gracefully", status.Code)
+	}
+
+	if stage, err := ref.Stop(context.Background(), time.Second); err != nil || stage != winproc.StopExited {
+		t.Errorf("second stop: got %s (%v), want %s", stage, err, winproc.StopExited)
+	}
+}
+
+func TestStopTerminate(t *testing.T) {
+	// A console program owns no windows, so closing it has no effect
+	command := exec.Command("ping", "-n", "30", "127.0.0.1")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	ref, err := winproc.FromCmd(command, processaccess.QueryLimitedInformation, processaccess.Synchronize, processaccess.Terminate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	stage, err := ref.Stop(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage != winproc.StopTerminate {
+		t.Fatalf("expected stop stage %s, got %s", winproc.StopTerminate, stage)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"io"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/nativeapi"
+	"github.com/gentlemanautomaton/winproc/psapi"
+	"golang.org/x/sys/windows"
+)
+
+// threadsSuspended returns true if every thread in the process with the
+// given process ID is suspended.
+func threadsSuspended(pid ID) (bool, error) {
+	snapshot, err := psapi.CreateSnapshot(psapi.SnapThread, 0)
+	if err != nil {
+		return false, err
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var threads int
+	entry, err := psapi.FirstThread(snapshot)
+	for ; err == nil; entry, err = psapi.NextThread(snapshot) {
+		if entry.OwnerProcessID != uint32(pid) {
+			continue
+		}
+
+		thread, err := windows.OpenThread(windows.THREAD_QUERY_LIMITED_INFORMATION, false, entry.ThreadID)
+		if err != nil {
+			if err == windows.ERROR_INVALID_PARAMETER {
+				// The thread has exited
+				continue
+			}
+			return false, err
+		}
+		count, err := nativeapi.ThreadSuspendCount(syscall.Handle(thread))
+		windows.CloseHandle(thread)
+		if err != nil {
+			return false, err
+		}
+		if count == 0 {
+			return false, nil
+		}
+		threads++
+	}
+	if err != io.EOF {
+		return false, err
+	}
+
+	return threads > 0, nil
+}
@@ -0,0 +1,44 @@
+package threadinfo
+
+// Class is a windows thread information class.
+type Class uint32
+
+// Windows thread information classes.
+const (
+	BasicInfo              Class = 0  // ThreadBasicInformation
+	Times                  Class = 1  // ThreadTimes
+	Priority               Class = 2  // ThreadPriority
+	BasePriority           Class = 3  // ThreadBasePriority
+	AffinityMask           Class = 4  // ThreadAffinityMask
+	ImpersonationToken     Class = 5  // ThreadImpersonationToken
+	DescriptorTableEntry   Class = 6  // ThreadDescriptorTableEntry
+	EnableAlignmentFixup   Class = 7  // ThreadEnableAlignmentFaultFixup
+	EventPair              Class = 8  // ThreadEventPair_Reusable
+	QuerySetWin32StartAddr Class = 9  // ThreadQuerySetWin32StartAddress
+	ZeroTLSCell            Class = 10 // ThreadZeroTlsCell
+	PerformanceCount       Class = 11 // ThreadPerformanceCount
+	AmILastThread          Class = 12 // ThreadAmILastThread
+	IdealProcessor         Class = 13 // ThreadIdealProcessor
+	PriorityBoost          Class = 14 // ThreadPriorityBoost
+	SetTLSArrayAddress     Class = 15 // ThreadSetTlsArrayAddress
+	IsIOPending            Class = 16 // ThreadIsIoPending
+	HideFromDebugger       Class = 17 // ThreadHideFromDebugger
+	BreakOnTermination     Class = 18 // ThreadBreakOnTermination
+	SwitchLegacyState      Class = 19 // ThreadSwitchLegacyState
+	IsTerminated           Class = 20 // ThreadIsTerminated
+	LastSystemCall         Class = 21 // ThreadLastSystemCall
+	IOPriority             Class = 22 // ThreadIoPriority
+	CycleTime              Class = 23 // ThreadCycleTime
+	PagePriority           Class = 24 // ThreadPagePriority
+	ActualBasePriority     Class = 25 // ThreadActualBasePriority
+	TEBInfo                Class = 26 // ThreadTebInformation
+	CSwitchMon             Class = 27 // ThreadCSwitchMon
+	CSwitchPmu             Class = 28 // ThreadCSwitchPmu
+	Wow64Context           Class = 29 // ThreadWow64Context
+	GroupInfo              Class = 30 // ThreadGroupInformation
+	UmsInfo                Class = 31 // ThreadUmsInformation
+	CounterProfiling       Class = 32 // ThreadCounterProfiling
+	IdealProcessorEx       Class = 33 // ThreadIdealProcessorEx
+	CPUAccountingInfo      Class = 34 // ThreadCpuAccountingInformation
+	SuspendCount           Class = 35 // ThreadSuspendCount
+)
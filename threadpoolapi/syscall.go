@@ -0,0 +1,164 @@
+//go:build windows
+// +build windows
+
+package threadpoolapi
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procRegisterWaitForSingleObject = modkernel32.NewProc("RegisterWaitForSingleObject")
+	procUnregisterWaitEx            = modkernel32.NewProc("UnregisterWaitEx")
+)
+
+// Wait flags.
+const (
+	ExecuteDefault      = 0x00000000 // WT_EXECUTEDEFAULT
+	ExecuteInWaitThread = 0x00000004 // WT_EXECUTEINWAITTHREAD
+	ExecuteOnlyOnce     = 0x00000008 // WT_EXECUTEONLYONCE
+)
+
+// Infinite is a timeout value that never elapses.
+const Infinite = 0xFFFFFFFF // INFINITE
+
+// RegisterWait directs a thread pool wait thread to call fn when object is
+// signaled or the timeout elapses. It calls the RegisterWaitForSingleObject
+// windows API function.
+//
+// A single thread pool wait thread can wait on up to 63 objects, so large
+// numbers of objects can be waited on by a small number of threads.
+//
+// fn is called on a thread pool thread. It must not block and must not call
+// UnregisterWait with a completion event for its own wait.
+//
+// The returned wait handle must be passed to UnregisterWait when the wait
+// is no longer needed, even if fn has already been called.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-registerwaitforsingleobject
+func RegisterWait(object syscall.Handle, timeout uint32, flags uint32, fn func(timedOut bool)) (wait syscall.Handle, err error) {
+	id := callbacks.add(fn)
+
+	r0, _, e := syscall.Syscall6(
+		procRegisterWaitForSingleObject.Addr(),
+		6,
+		uintptr(unsafe.Pointer(&wait)),
+		uintptr(object),
+		waitCallback,
+		id,
+		uintptr(timeout),
+		uintptr(flags))
+	if r0 == 0 {
+		callbacks.remove(id)
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+		return 0, err
+	}
+
+	waits.add(wait, id)
+	return wait, nil
+}
+
+// UnregisterWait cancels a wait that was registered by RegisterWait. If
+// blocking is true it waits for any callbacks in progress to complete
+// before returning. It calls the UnregisterWaitEx windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/threadpoollegacyapiset/nf-threadpoollegacyapiset-unregisterwaitex
+func UnregisterWait(wait syscall.Handle, blocking bool) (err error) {
+	var completion uintptr
+	if blocking {
+		completion = uintptr(syscall.InvalidHandle)
+	}
+
+	r0, _, e := syscall.Syscall(
+		procUnregisterWaitEx.Addr(),
+		2,
+		uintptr(wait),
+		completion,
+		0)
+	if r0 == 0 && e != windows.ERROR_IO_PENDING {
+		// ERROR_IO_PENDING indicates that a callback is still running,
+		// which is expected for non-blocking calls
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+
+	if id, ok := waits.remove(wait); ok && (blocking || err == nil) {
+		callbacks.remove(id)
+	}
+	return
+}
+
+// waitCallback is shared by all calls to RegisterWait, because the number
+// of callbacks that can be created is limited. The application defined
+// value passed to it identifies the function to be called.
+var waitCallback = syscall.NewCallback(func(id uintptr, timedOut uintptr) uintptr {
+	if fn := callbacks.get(id); fn != nil {
+		fn(timedOut&0xFF != 0)
+	}
+	return 0
+})
+
+var (
+	callbacks = callbackSet{fns: make(map[uintptr]func(bool))}
+	waits     = waitSet{ids: make(map[syscall.Handle]uintptr)}
+)
+
+// callbackSet keeps track of the wait functions that are in use.
+type callbackSet struct {
+	mutex sync.RWMutex
+	next  uintptr
+	fns   map[uintptr]func(bool)
+}
+
+func (set *callbackSet) add(fn func(bool)) uintptr {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	set.next++
+	set.fns[set.next] = fn
+	return set.next
+}
+
+func (set *callbackSet) get(id uintptr) func(bool) {
+	set.mutex.RLock()
+	defer set.mutex.RUnlock()
+	return set.fns[id]
+}
+
+func (set *callbackSet) remove(id uintptr) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	delete(set.fns, id)
+}
+
+// waitSet maps wait handles to the IDs of their callback functions.
+type waitSet struct {
+	mutex sync.Mutex
+	ids   map[syscall.Handle]uintptr
+}
+
+func (set *waitSet) add(wait syscall.Handle, id uintptr) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	set.ids[wait] = id
+}
+
+func (set *waitSet) remove(wait syscall.Handle) (id uintptr, ok bool) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	id, ok = set.ids[wait]
+	delete(set.ids, wait)
+	return
+}
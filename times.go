@@ -6,14 +6,75 @@ package winproc
 import (
 	"syscall"
 	"time"
+
+	"golang.org/x/sys/windows/registry"
 )
 
 // Times holds time information about a windows process.
+//
+// Kernel and User are updated by the system on each clock tick, which is
+// typically 15.6 milliseconds. Cycles is precise, which makes it better
+// suited to measuring CPU usage over short intervals.
 type Times struct {
 	Creation time.Time     // Process creation time
 	Exit     time.Time     // Process exit time
 	Kernel   time.Duration // Time spent in kernel mode
 	User     time.Duration // Time spent in user mode
+	Cycles   uint64        // CPU clock cycles used by all threads
+}
+
+// CPU returns the total time spent in kernel and user mode.
+func (t Times) CPU() time.Duration {
+	return t.Kernel + t.User
+}
+
+// Usage returns the CPU usage of the process between an earlier sample and
+// t, which were taken elapsed apart. A value of 1 means that the process
+// kept one processor busy for the whole interval. Processes with several
+// busy threads can exceed 1.
+//
+// If both samples include cycle counts and rate is positive, usage is
+// derived from the cycle counts. Otherwise it is derived from the kernel
+// and user times, which are only accurate over intervals much longer than
+// a clock tick.
+func (t Times) Usage(earlier Times, elapsed time.Duration, rate CycleRate) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	if rate > 0 && earlier.Cycles != 0 && t.Cycles >= earlier.Cycles {
+		cycles := float64(t.Cycles - earlier.Cycles)
+		return cycles / (float64(rate) * elapsed.Seconds())
+	}
+	cpu := t.CPU() - earlier.CPU()
+	if cpu < 0 {
+		return 0
+	}
+	return cpu.Seconds() / elapsed.Seconds()
+}
+
+// CycleRate is the rate at which processor cycles are counted, in cycles
+// per second.
+type CycleRate uint64
+
+// ProcessorCycleRate returns the nominal rate at which the processor
+// counts cycles. It is read from the processor description recorded by
+// the system at startup.
+//
+// Cycle counts are based on the processor's time stamp counter, which
+// runs at the nominal rate regardless of power management on modern
+// processors.
+func ProcessorCycleRate() (CycleRate, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `HARDWARE\DESCRIPTION\System\CentralProcessor\0`, registry.QUERY_VALUE)
+	if err != nil {
+		return 0, err
+	}
+	defer key.Close()
+
+	mhz, _, err := key.GetIntegerValue("~MHz")
+	if err != nil {
+		return 0, err
+	}
+	return CycleRate(mhz * 1000000), nil
 }
 
 func timeFromFiletime(ft syscall.Filetime) time.Time {
@@ -0,0 +1,83 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc"
+)
+
+func TestTimesUsage(t *testing.T) {
+	const rate = winproc.CycleRate(2000000000) // 2 GHz
+
+	tests := []struct {
+		Name    string
+		Earlier winproc.Times
+		Later   winproc.Times
+		Elapsed time.Duration
+		Rate    winproc.CycleRate
+		Usage   float64
+	}{
+		{"Cycles", winproc.Times{Cycles: 1000}, winproc.Times{Cycles: 1000 + 500000000}, time.Second, rate, 0.25},
+		{"CyclesShortInterval", winproc.Times{Cycles: 1}, winproc.Times{Cycles: 1 + 2000000}, time.Millisecond, rate, 1},
+		{"CyclesMultipleThreads", winproc.Times{Cycles: 1}, winproc.Times{Cycles: 1 + 6000000000}, time.Second, rate, 3},
+		{"TicksWithoutRate", winproc.Times{User: time.Second}, winproc.Times{User: 2 * time.Second, Kernel: time.Second}, 4 * time.Second, 0, 0.5},
+		{"TicksWithoutCycles", winproc.Times{}, winproc.Times{User: time.Second}, 2 * time.Second, rate, 0.5},
+		{"NoInterval", winproc.Times{Cycles: 1}, winproc.Times{Cycles: 2}, 0, rate, 0},
+		{"Regressed", winproc.Times{User: time.Second}, winproc.Times{}, time.Second, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			usage := test.Later.Usage(test.Earlier, test.Elapsed, test.Rate)
+			if math.Abs(usage-test.Usage) > 1e-9 {
+				t.Fatalf("usage is %v, expected %v", usage, test.Usage)
+			}
+		})
+	}
+}
+
+func TestTimesUsageLive(t *testing.T) {
+	rate, err := winproc.ProcessorCycleRate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate == 0 {
+		t.Fatal("the processor cycle rate is zero")
+	}
+
+	ref, err := winproc.Open(winproc.ID(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	sample := func() (winproc.Times, time.Time) {
+		times, err := ref.Times()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if times.Cycles, err = ref.CycleTime(); err != nil {
+			t.Fatal(err)
+		}
+		return times, time.Now()
+	}
+
+	earlier, start := sample()
+
+	// Keep one processor busy for a while
+	for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); {
+	}
+
+	later, end := sample()
+	usage := later.Usage(earlier, end.Sub(start), rate)
+	t.Logf("Usage: %.2f (rate %d)", usage, rate)
+	if usage < 0.25 || usage > 4 {
+		t.Fatalf("usage of %.2f is implausible for a busy loop", usage)
+	}
+}
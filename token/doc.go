@@ -0,0 +1,9 @@
+// Package token inspects and adjusts the access tokens of the current
+// process and thread. It can check whether the process is elevated and
+// temporarily enable privileges such as SeDebugPrivilege.
+//
+// Privileges can only be enabled if they are held by the token. Enabling a
+// privilege in the process token affects every thread that isn't
+// impersonating, so changes should be restored as soon as they are no
+// longer needed.
+package token
@@ -0,0 +1,28 @@
+package token
+
+// ElevationType describes the relationship between a token and its linked
+// token under user account control.
+type ElevationType uint32
+
+// Token elevation types.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ne-winnt-token_elevation_type
+const (
+	ElevationDefault ElevationType = 1 // TokenElevationTypeDefault
+	ElevationFull    ElevationType = 2 // TokenElevationTypeFull
+	ElevationLimited ElevationType = 3 // TokenElevationTypeLimited
+)
+
+// String returns a string representation of the elevation type.
+func (t ElevationType) String() string {
+	switch t {
+	case ElevationDefault:
+		return "default"
+	case ElevationFull:
+		return "full"
+	case ElevationLimited:
+		return "limited"
+	default:
+		return "unknown"
+	}
+}
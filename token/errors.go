@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package token
+
+import "errors"
+
+var (
+	// ErrClosed is returned when a token handle needed for an action has
+	// already been closed.
+	ErrClosed = errors.New("the token handle has been closed")
+
+	// ErrNotHeld is returned when a privilege can't be enabled because it
+	// isn't held by the token.
+	ErrNotHeld = errors.New("the privilege is not held by the token")
+
+	// ErrUnknownPrivilege is returned when a privilege name isn't
+	// recognized by the system.
+	ErrUnknownPrivilege = errors.New("the privilege name is not recognized")
+)
@@ -0,0 +1,43 @@
+//go:build windows
+// +build windows
+
+package token
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// Lookup returns the locally unique identifier of the privilege with the
+// given name. It returns ErrUnknownPrivilege if the system doesn't
+// recognize the name.
+func Lookup(name Name) (LUID, error) {
+	utf16, err := syscall.UTF16PtrFromString(string(name))
+	if err != nil {
+		return LUID{}, err
+	}
+
+	var luid windows.LUID
+	if err := windows.LookupPrivilegeValue(nil, utf16, &luid); err != nil {
+		if err == windows.ERROR_NO_SUCH_PRIVILEGE {
+			return LUID{}, fmt.Errorf("%s: %w", name, ErrUnknownPrivilege)
+		}
+		return LUID{}, err
+	}
+
+	return LUID{LowPart: luid.LowPart, HighPart: luid.HighPart}, nil
+}
+
+// Name returns the name of the privilege identified by the LUID.
+func (luid LUID) Name() (Name, error) {
+	name, err := lookupPrivilegeName(luid)
+	if err != nil {
+		if err == windows.ERROR_NO_SUCH_PRIVILEGE {
+			return "", fmt.Errorf("%s: %w", luid, ErrUnknownPrivilege)
+		}
+		return "", err
+	}
+	return Name(name), nil
+}
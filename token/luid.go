@@ -0,0 +1,20 @@
+package token
+
+import "fmt"
+
+// LUID is a locally unique identifier. The system uses LUIDs to identify
+// privileges on the local machine.
+type LUID struct {
+	LowPart  uint32
+	HighPart int32
+}
+
+// Uint64 returns the LUID as a 64-bit value.
+func (luid LUID) Uint64() uint64 {
+	return uint64(uint32(luid.HighPart))<<32 | uint64(luid.LowPart)
+}
+
+// String returns a string representation of the LUID.
+func (luid LUID) String() string {
+	return fmt.Sprintf("0x%X", luid.Uint64())
+}
@@ -0,0 +1,115 @@
+package token
+
+import "slices"
+
+// Name is the programmatic name of a privilege, such as SeDebugPrivilege.
+type Name string
+
+// Windows privilege names.
+//
+// https://docs.microsoft.com/en-us/windows/win32/secauthz/privilege-constants
+const (
+	AssignPrimaryToken             Name = "SeAssignPrimaryTokenPrivilege"             // SE_ASSIGNPRIMARYTOKEN_NAME
+	Audit                          Name = "SeAuditPrivilege"                          // SE_AUDIT_NAME
+	Backup                         Name = "SeBackupPrivilege"                         // SE_BACKUP_NAME
+	ChangeNotify                   Name = "SeChangeNotifyPrivilege"                   // SE_CHANGE_NOTIFY_NAME
+	CreateGlobal                   Name = "SeCreateGlobalPrivilege"                   // SE_CREATE_GLOBAL_NAME
+	CreatePagefile                 Name = "SeCreatePagefilePrivilege"                 // SE_CREATE_PAGEFILE_NAME
+	CreatePermanent                Name = "SeCreatePermanentPrivilege"                // SE_CREATE_PERMANENT_NAME
+	CreateSymbolicLink             Name = "SeCreateSymbolicLinkPrivilege"             // SE_CREATE_SYMBOLIC_LINK_NAME
+	CreateToken                    Name = "SeCreateTokenPrivilege"                    // SE_CREATE_TOKEN_NAME
+	Debug                          Name = "SeDebugPrivilege"                          // SE_DEBUG_NAME
+	DelegateSessionUserImpersonate Name = "SeDelegateSessionUserImpersonatePrivilege" // SE_DELEGATE_SESSION_USER_IMPERSONATE_NAME
+	EnableDelegation               Name = "SeEnableDelegationPrivilege"               // SE_ENABLE_DELEGATION_NAME
+	Impersonate                    Name = "SeImpersonatePrivilege"                    // SE_IMPERSONATE_NAME
+	IncreaseBasePriority           Name = "SeIncreaseBasePriorityPrivilege"           // SE_INC_BASE_PRIORITY_NAME
+	IncreaseQuota                  Name = "SeIncreaseQuotaPrivilege"                  // SE_INCREASE_QUOTA_NAME
+	IncreaseWorkingSet             Name = "SeIncreaseWorkingSetPrivilege"             // SE_INC_WORKING_SET_NAME
+	LoadDriver                     Name = "SeLoadDriverPrivilege"                     // SE_LOAD_DRIVER_NAME
+	LockMemory                     Name = "SeLockMemoryPrivilege"                     // SE_LOCK_MEMORY_NAME
+	MachineAccount                 Name = "SeMachineAccountPrivilege"                 // SE_MACHINE_ACCOUNT_NAME
+	ManageVolume                   Name = "SeManageVolumePrivilege"                   // SE_MANAGE_VOLUME_NAME
+	ProfileSingleProcess           Name = "SeProfileSingleProcessPrivilege"           // SE_PROF_SINGLE_PROCESS_NAME
+	Relabel                        Name = "SeRelabelPrivilege"                        // SE_RELABEL_NAME
+	RemoteShutdown                 Name = "SeRemoteShutdownPrivilege"                 // SE_REMOTE_SHUTDOWN_NAME
+	Restore                        Name = "SeRestorePrivilege"                        // SE_RESTORE_NAME
+	Security                       Name = "SeSecurityPrivilege"                       // SE_SECURITY_NAME
+	Shutdown                       Name = "SeShutdownPrivilege"                       // SE_SHUTDOWN_NAME
+	SyncAgent                      Name = "SeSyncAgentPrivilege"                      // SE_SYNC_AGENT_NAME
+	SystemEnvironment              Name = "SeSystemEnvironmentPrivilege"              // SE_SYSTEM_ENVIRONMENT_NAME
+	SystemProfile                  Name = "SeSystemProfilePrivilege"                  // SE_SYSTEM_PROFILE_NAME
+	SystemTime                     Name = "SeSystemtimePrivilege"                     // SE_SYSTEMTIME_NAME
+	TakeOwnership                  Name = "SeTakeOwnershipPrivilege"                  // SE_TAKE_OWNERSHIP_NAME
+	TCB                            Name = "SeTcbPrivilege"                            // SE_TCB_NAME
+	TimeZone                       Name = "SeTimeZonePrivilege"                       // SE_TIME_ZONE_NAME
+	TrustedCredManAccess           Name = "SeTrustedCredManAccessPrivilege"           // SE_TRUSTED_CREDMAN_ACCESS_NAME
+	Undock                         Name = "SeUndockPrivilege"                         // SE_UNDOCK_NAME
+)
+
+// descriptions maps privilege names to their display names.
+var descriptions = map[Name]string{
+	AssignPrimaryToken:             "Replace a process level token",
+	Audit:                          "Generate security audits",
+	Backup:                         "Back up files and directories",
+	ChangeNotify:                   "Bypass traverse checking",
+	CreateGlobal:                   "Create global objects",
+	CreatePagefile:                 "Create a pagefile",
+	CreatePermanent:                "Create permanent shared objects",
+	CreateSymbolicLink:             "Create symbolic links",
+	CreateToken:                    "Create a token object",
+	Debug:                          "Debug programs",
+	DelegateSessionUserImpersonate: "Obtain an impersonation token for another user in the same session",
+	EnableDelegation:               "Enable computer and user accounts to be trusted for delegation",
+	Impersonate:                    "Impersonate a client after authentication",
+	IncreaseBasePriority:           "Increase scheduling priority",
+	IncreaseQuota:                  "Adjust memory quotas for a process",
+	IncreaseWorkingSet:             "Increase a process working set",
+	LoadDriver:                     "Load and unload device drivers",
+	LockMemory:                     "Lock pages in memory",
+	MachineAccount:                 "Add workstations to domain",
+	ManageVolume:                   "Perform volume maintenance tasks",
+	ProfileSingleProcess:           "Profile single process",
+	Relabel:                        "Modify an object label",
+	RemoteShutdown:                 "Force shutdown from a remote system",
+	Restore:                        "Restore files and directories",
+	Security:                       "Manage auditing and security log",
+	Shutdown:                       "Shut down the system",
+	SyncAgent:                      "Synchronize directory service data",
+	SystemEnvironment:              "Modify firmware environment values",
+	SystemProfile:                  "Profile system performance",
+	SystemTime:                     "Change the system time",
+	TakeOwnership:                  "Take ownership of files or other objects",
+	TCB:                            "Act as part of the operating system",
+	TimeZone:                       "Change the time zone",
+	TrustedCredManAccess:           "Access Credential Manager as a trusted caller",
+	Undock:                         "Remove computer from docking station",
+}
+
+// Names returns the names of all privileges known to this package.
+func Names() []Name {
+	names := make([]Name, 0, len(descriptions))
+	for name := range descriptions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Known returns true if n is one of the privilege names known to this
+// package.
+func (n Name) Known() bool {
+	_, ok := descriptions[n]
+	return ok
+}
+
+// Description returns the display name of the privilege, as shown in the
+// local security policy editor. It returns an empty string if the
+// privilege is unknown.
+func (n Name) Description() string {
+	return descriptions[n]
+}
+
+// String returns the privilege name.
+func (n Name) String() string {
+	return string(n)
+}
@@ -0,0 +1,26 @@
+package token
+
+// Attributes hold the attributes of a privilege within a token.
+type Attributes uint32
+
+// Privilege attributes.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-token_privileges
+const (
+	EnabledByDefault Attributes = 0x00000001 // SE_PRIVILEGE_ENABLED_BY_DEFAULT
+	Enabled          Attributes = 0x00000002 // SE_PRIVILEGE_ENABLED
+	Removed          Attributes = 0x00000004 // SE_PRIVILEGE_REMOVED
+	UsedForAccess    Attributes = 0x80000000 // SE_PRIVILEGE_USED_FOR_ACCESS
+)
+
+// Privilege describes a privilege held by a token.
+type Privilege struct {
+	LUID       LUID
+	Name       Name
+	Attributes Attributes
+}
+
+// Enabled returns true if the privilege is enabled.
+func (p Privilege) Enabled() bool {
+	return p.Attributes&Enabled != 0
+}
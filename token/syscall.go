@@ -0,0 +1,83 @@
+//go:build windows
+// +build windows
+
+package token
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modadvapi32 = windows.NewLazySystemDLL("advapi32.dll")
+
+	procAdjustTokenPrivileges = modadvapi32.NewProc("AdjustTokenPrivileges")
+	procLookupPrivilegeNameW  = modadvapi32.NewProc("LookupPrivilegeNameW")
+)
+
+// adjustPrivileges calls the AdjustTokenPrivileges windows API function.
+// If previous is not nil it receives the prior state of each privilege
+// that was changed.
+//
+// Unlike the version in x/sys/windows, it reports ERROR_NOT_ALL_ASSIGNED,
+// which is returned by successful calls that couldn't adjust every
+// privilege.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/securitybaseapi/nf-securitybaseapi-adjusttokenprivileges
+func adjustPrivileges(token windows.Token, state []byte, previous []byte) (returned uint32, err error) {
+	var prevPtr uintptr
+	if len(previous) > 0 {
+		prevPtr = uintptr(unsafe.Pointer(&previous[0]))
+	}
+
+	r0, _, e := syscall.Syscall6(
+		procAdjustTokenPrivileges.Addr(),
+		6,
+		uintptr(token),
+		0,
+		uintptr(unsafe.Pointer(&state[0])),
+		uintptr(len(previous)),
+		prevPtr,
+		uintptr(unsafe.Pointer(&returned)))
+	if r0 == 0 || e == windows.ERROR_NOT_ALL_ASSIGNED {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// lookupPrivilegeName calls the LookupPrivilegeNameW windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-lookupprivilegenamew
+func lookupPrivilegeName(luid LUID) (string, error) {
+	raw := windows.LUID{LowPart: luid.LowPart, HighPart: luid.HighPart}
+	buffer := make([]uint16, 64)
+	for {
+		length := uint32(len(buffer))
+		r0, _, e := syscall.Syscall6(
+			procLookupPrivilegeNameW.Addr(),
+			4,
+			0,
+			uintptr(unsafe.Pointer(&raw)),
+			uintptr(unsafe.Pointer(&buffer[0])),
+			uintptr(unsafe.Pointer(&length)),
+			0,
+			0)
+		if r0 != 0 {
+			return syscall.UTF16ToString(buffer[:length]), nil
+		}
+		switch e {
+		case syscall.ERROR_INSUFFICIENT_BUFFER:
+			buffer = make([]uint16, length+1)
+		case 0:
+			return "", syscall.EINVAL
+		default:
+			return "", syscall.Errno(e)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+//go:build windows
+// +build windows
+
+package token
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// access holds the access rights needed for all token operations.
+const access = windows.TOKEN_QUERY | windows.TOKEN_ADJUST_PRIVILEGES
+
+// A Token is a reference to an access token of the current process or
+// thread. It manages an open system handle internally.
+//
+// Each token must be closed when it is no longer needed.
+type Token struct {
+	mutex  sync.RWMutex
+	handle windows.Token
+	revert bool // Stop impersonating when closed
+}
+
+// RestoreFunc restores privileges to the state they were in before they
+// were adjusted. Only the first call has any effect.
+type RestoreFunc func() error
+
+// OpenProcess returns a reference to the primary access token of the
+// current process.
+//
+// It is the caller's responsibility to close the token when finished
+// with it.
+func OpenProcess() (*Token, error) {
+	var handle windows.Token
+	if err := windows.OpenProcessToken(windows.CurrentProcess(), access, &handle); err != nil {
+		return nil, err
+	}
+	return &Token{handle: handle}, nil
+}
+
+// OpenThread returns a reference to the access token of the calling
+// thread. Adjusting it affects only the calling thread.
+//
+// If the thread isn't impersonating, it begins impersonating the current
+// process so that it has a token of its own. It stops impersonating when
+// the token is closed.
+//
+// The caller must lock the goroutine to its thread with runtime.LockOSThread
+// before calling OpenThread, and keep it locked until the token has been
+// closed. It is the caller's responsibility to close the token when
+// finished with it.
+func OpenThread() (*Token, error) {
+	var handle windows.Token
+	err := windows.OpenThreadToken(windows.CurrentThread(), access, true, &handle)
+	if err == nil {
+		return &Token{handle: handle}, nil
+	}
+	if err != windows.ERROR_NO_TOKEN {
+		return nil, err
+	}
+
+	if err := windows.ImpersonateSelf(windows.SecurityImpersonation); err != nil {
+		return nil, err
+	}
+	if err := windows.OpenThreadToken(windows.CurrentThread(), access, true, &handle); err != nil {
+		windows.RevertToSelf()
+		return nil, err
+	}
+	return &Token{handle: handle, revert: true}, nil
+}
+
+// Close releases the token handle. If the token was opened by OpenThread
+// and the thread began impersonating, it stops impersonating.
+//
+// Close must be called from the thread that opened the token.
+func (t *Token) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.handle == 0 {
+		return ErrClosed
+	}
+
+	err := t.handle.Close()
+	t.handle = 0
+	if t.revert {
+		if revertErr := windows.RevertToSelf(); err == nil {
+			err = revertErr
+		}
+	}
+	return err
+}
+
+// Elevated returns true if the token is elevated.
+func (t *Token) Elevated() (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.handle == 0 {
+		return false, ErrClosed
+	}
+
+	return t.handle.IsElevated(), nil
+}
+
+// ElevationType returns the elevation type of the token.
+func (t *Token) ElevationType() (ElevationType, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.handle == 0 {
+		return 0, ErrClosed
+	}
+
+	var (
+		value    uint32
+		returned uint32
+	)
+	if err := windows.GetTokenInformation(t.handle, windows.TokenElevationType, (*byte)(unsafe.Pointer(&value)), uint32(unsafe.Sizeof(value)), &returned); err != nil {
+		return 0, err
+	}
+	return ElevationType(value), nil
+}
+
+// Privileges returns the privileges held by the token.
+func (t *Token) Privileges() ([]Privilege, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.handle == 0 {
+		return nil, ErrClosed
+	}
+
+	return t.privileges()
+}
+
+// Enabled returns true if the token holds the named privilege and it is
+// enabled.
+func (t *Token) Enabled(name Name) (bool, error) {
+	privileges, err := t.Privileges()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range privileges {
+		if p.Name == name {
+			return p.Enabled(), nil
+		}
+	}
+	return false, nil
+}
+
+// Enable enables the named privileges. It returns a function that
+// restores them to their previous state.
+//
+// If any of the privileges aren't held by the token, none of them are
+// enabled and an error matching ErrNotHeld is returned.
+func (t *Token) Enable(names ...Name) (RestoreFunc, error) {
+	return t.adjust(names, Enabled)
+}
+
+// Disable disables the named privileges. It returns a function that
+// restores them to their previous state.
+//
+// Privileges that aren't held by the token are ignored.
+func (t *Token) Disable(names ...Name) (RestoreFunc, error) {
+	return t.adjust(names, 0)
+}
+
+// Enable enables the named privileges in the primary access token of the
+// current process. It returns a function that restores them to their
+// previous state.
+//
+// The privileges are enabled for every thread in the process that isn't
+// impersonating. Use OpenThread to enable privileges for a single thread.
+func Enable(names ...Name) (RestoreFunc, error) {
+	t, err := OpenProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	restore, err := t.Enable(names...)
+	if err != nil {
+		t.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer t.Close()
+		return restore()
+	}, nil
+}
+
+// adjust sets the attributes of the named privileges.
+func (t *Token) adjust(names []Name, attributes Attributes) (RestoreFunc, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.handle == 0 {
+		return nil, ErrClosed
+	}
+
+	// Look up each privilege and make sure it's held when enabling
+	held, err := t.privileges()
+	if err != nil {
+		return nil, err
+	}
+	isHeld := make(map[LUID]bool, len(held))
+	for _, p := range held {
+		isHeld[p.LUID] = true
+	}
+
+	var (
+		luids   []LUID
+		missing []string
+	)
+	for _, name := range names {
+		luid, err := Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		if !isHeld[luid] {
+			missing = append(missing, string(name))
+			continue
+		}
+		luids = append(luids, luid)
+	}
+	if len(missing) > 0 && attributes&Enabled != 0 {
+		return nil, fmt.Errorf("%s: %w", strings.Join(missing, ", "), ErrNotHeld)
+	}
+	if len(luids) == 0 {
+		return func() error { return nil }, nil
+	}
+
+	state := newPrivilegeState(len(luids))
+	entries := state.entries()
+	for i := range entries {
+		entries[i].Luid = windows.LUID{LowPart: luids[i].LowPart, HighPart: luids[i].HighPart}
+		entries[i].Attributes = uint32(attributes)
+	}
+
+	previous := newPrivilegeState(len(luids))
+	if _, err := adjustPrivileges(t.handle, state, previous); err != nil {
+		if err == windows.ERROR_NOT_ALL_ASSIGNED {
+			// Undo whatever was changed
+			adjustPrivileges(t.handle, previous, nil)
+			return nil, fmt.Errorf("%s: %w", joinNames(names), ErrNotHeld)
+		}
+		return nil, err
+	}
+
+	var once sync.Once
+	return func() (err error) {
+		once.Do(func() {
+			err = t.restore(previous)
+		})
+		return
+	}, nil
+}
+
+// restore applies the previous state of privileges that were adjusted.
+func (t *Token) restore(previous privilegeState) error {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.handle == 0 {
+		return ErrClosed
+	}
+	if previous.count() == 0 {
+		return nil
+	}
+
+	_, err := adjustPrivileges(t.handle, previous, nil)
+	return err
+}
+
+// privileges returns the privileges held by the token. The caller must
+// hold a read lock.
+func (t *Token) privileges() ([]Privilege, error) {
+	var returned uint32
+	buffer := make([]byte, 512)
+	for {
+		err := windows.GetTokenInformation(t.handle, windows.TokenPrivileges, &buffer[0], uint32(len(buffer)), &returned)
+		if err == nil {
+			break
+		}
+		if err != windows.ERROR_INSUFFICIENT_BUFFER {
+			return nil, err
+		}
+		buffer = make([]byte, returned)
+	}
+
+	state := privilegeState(buffer)
+	entries := state.entries()
+	privileges := make([]Privilege, 0, len(entries))
+	for _, entry := range entries {
+		luid := LUID{LowPart: entry.Luid.LowPart, HighPart: entry.Luid.HighPart}
+		name, _ := luid.Name()
+		privileges = append(privileges, Privilege{
+			LUID:       luid,
+			Name:       name,
+			Attributes: Attributes(entry.Attributes),
+		})
+	}
+	return privileges, nil
+}
+
+// joinNames returns a comma-separated list of privilege names.
+func joinNames(names []Name) string {
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = string(name)
+	}
+	return strings.Join(s, ", ")
+}
+
+// privilegeState is a buffer holding a TOKEN_PRIVILEGES structure.
+type privilegeState []byte
+
+// newPrivilegeState returns a buffer large enough to hold n privileges.
+func newPrivilegeState(n int) privilegeState {
+	var entry windows.LUIDAndAttributes
+	size := int(unsafe.Offsetof(windows.Tokenprivileges{}.Privileges)) + n*int(unsafe.Sizeof(entry))
+	state := make(privilegeState, size)
+	state.header().PrivilegeCount = uint32(n)
+	return state
+}
+
+func (state privilegeState) header() *windows.Tokenprivileges {
+	return (*windows.Tokenprivileges)(unsafe.Pointer(&state[0]))
+}
+
+func (state privilegeState) count() int {
+	return int(state.header().PrivilegeCount)
+}
+
+func (state privilegeState) entries() []windows.LUIDAndAttributes {
+	return state.header().AllPrivileges()
+}
@@ -0,0 +1,113 @@
+//go:build windows
+// +build windows
+
+package token_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc/token"
+)
+
+func TestLookup(t *testing.T) {
+	for _, name := range token.Names() {
+		luid, err := token.Lookup(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		resolved, err := luid.Name()
+		if err != nil {
+			t.Errorf("%s: %v", luid, err)
+			continue
+		}
+		if resolved != name {
+			t.Errorf("%s resolved to %s instead of %s", luid, resolved, name)
+		}
+	}
+
+	if _, err := token.Lookup("SeMadeUpPrivilege"); !errors.Is(err, token.ErrUnknownPrivilege) {
+		t.Errorf("expected %v, got %v", token.ErrUnknownPrivilege, err)
+	}
+}
+
+func TestProcessToken(t *testing.T) {
+	tok, err := token.OpenProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tok.Close()
+
+	if _, err := tok.Elevated(); err != nil {
+		t.Error(err)
+	}
+	if elevation, err := tok.ElevationType(); err != nil {
+		t.Error(err)
+	} else {
+		t.Logf("Elevation: %s", elevation)
+	}
+
+	privileges, err := tok.Privileges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range privileges {
+		t.Logf("%s (%s): enabled=%t", p.Name, p.LUID, p.Enabled())
+	}
+}
+
+func TestThreadToken(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	tok, err := token.OpenThread()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tok.Close()
+
+	// SeChangeNotifyPrivilege is held by every token and enabled by default
+	restore, err := tok.Disable(token.ChangeNotify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := tok.Enabled(token.ChangeNotify); err != nil {
+		t.Fatal(err)
+	} else if enabled {
+		t.Fatalf("%s was not disabled", token.ChangeNotify)
+	}
+
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := tok.Enabled(token.ChangeNotify); err != nil {
+		t.Fatal(err)
+	} else if !enabled {
+		t.Fatalf("%s was not restored", token.ChangeNotify)
+	}
+}
+
+func TestEnableNotHeld(t *testing.T) {
+	tok, err := token.OpenProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tok.Close()
+
+	// Ordinary accounts don't hold SeCreateTokenPrivilege
+	privileges, err := tok.Privileges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range privileges {
+		if p.Name == token.CreateToken {
+			t.Skipf("%s is held by the test process", token.CreateToken)
+		}
+	}
+
+	if _, err := tok.Enable(token.CreateToken); !errors.Is(err, token.ErrNotHeld) {
+		t.Fatalf("expected %v, got %v", token.ErrNotHeld, err)
+	}
+}
@@ -3,33 +3,104 @@
 
 package winproc
 
+// A TreeOption adjusts the way a process tree is formed.
+type TreeOption int
+
+const (
+	// AttachConsoleHosts is a tree option that attaches each console host
+	// process as a pseudo-child of the console application it serves,
+	// instead of placing it under its actual parent.
+	//
+	// It relies on information gathered by the CollectConsoleHosts option.
+	AttachConsoleHosts TreeOption = 1 << iota
+)
+
+// Contains returns true if o contains b.
+func (o TreeOption) Contains(b TreeOption) bool {
+	return o&b == b
+}
+
 // Tree creates a hierarchy out of a list of processes.
-func Tree(procs []Process) []Node {
+func Tree(procs []Process, options ...TreeOption) []Node {
+	var opts TreeOption
+	for _, option := range options {
+		opts |= option
+	}
+
+	// Determine the parent of each node
+	parents := make(map[ID]ID, len(procs))
+	for _, proc := range procs {
+		parents[proc.ID] = proc.ParentID
+	}
+	var pseudo map[ID]bool
+	if opts.Contains(AttachConsoleHosts) {
+		pseudo = attachConsoleHosts(procs, parents)
+	}
+
 	// Build a lookup for each node and a map from parents to children
 	nodes := make(map[ID]Process, len(procs))
 	hierarchy := make(map[ID][]ID, len(procs))
 	for _, proc := range procs {
 		nodes[proc.ID] = proc
-		if proc.ID != proc.ParentID {
-			hierarchy[proc.ParentID] = append(hierarchy[proc.ParentID], proc.ID)
+		if parent := parents[proc.ID]; proc.ID != parent {
+			hierarchy[parent] = append(hierarchy[parent], proc.ID)
 		}
 	}
 
 	// Build a tree from the roots
 	var roots []Node
 	for _, proc := range procs {
-		if proc.ID != findRoot(proc.ID, proc.ParentID, nodes) {
+		if proc.ID != findRoot(proc.ID, parents[proc.ID], nodes, parents) {
 			continue
 		}
 		roots = append(roots, Node{
 			Process:  proc,
-			Children: childNodes(proc.ID, proc.ID, nodes, hierarchy),
+			Children: childNodes(proc.ID, proc.ID, nodes, hierarchy, pseudo),
+			Pseudo:   pseudo[proc.ID],
 		})
 	}
 	return roots
 }
 
-func findRoot(pid, parent ID, nodes map[ID]Process) ID {
+// attachConsoleHosts updates parents so that each console host in procs
+// becomes a child of the first console application it serves. Applications
+// whose parent is served by the same host are skipped, so that the host is
+// attached to the top of the console's process tree.
+//
+// It returns the set of console hosts that were attached.
+func attachConsoleHosts(procs []Process, parents map[ID]ID) map[ID]bool {
+	clients := make(map[ID]map[ID]bool) // Maps console hosts to their clients
+	for _, proc := range procs {
+		host := proc.ConsoleHostID
+		if host == 0 || host == proc.ID {
+			continue
+		}
+		if _, found := parents[host]; !found {
+			continue // The console host isn't in the list
+		}
+		if clients[host] == nil {
+			clients[host] = make(map[ID]bool)
+		}
+		clients[host][proc.ID] = true
+	}
+
+	attached := make(map[ID]bool, len(clients))
+	for _, proc := range procs {
+		host := proc.ConsoleHostID
+		if attached[host] || !clients[host][proc.ID] {
+			continue
+		}
+		if clients[host][proc.ParentID] && proc.ParentID != proc.ID {
+			continue // The parent shares the same console
+		}
+		parents[host] = proc.ID
+		attached[host] = true
+	}
+
+	return attached
+}
+
+func findRoot(pid, parent ID, nodes map[ID]Process, parents map[ID]ID) ID {
 	// Keep track of the PIDs we've seen
 	seen := make([]ID, 0, 8)
 
@@ -40,8 +111,7 @@ func findRoot(pid, parent ID, nodes map[ID]Process) ID {
 		}
 
 		// Processes with an inaccessible parent are roots
-		node, found := nodes[parent]
-		if !found {
+		if _, found := nodes[parent]; !found {
 			return pid
 		}
 
@@ -49,7 +119,7 @@ func findRoot(pid, parent ID, nodes map[ID]Process) ID {
 		seen = append(seen, pid)
 
 		// Advance
-		pid, parent = parent, node.ParentID
+		pid, parent = parent, parents[parent]
 
 		// If we encounter a PID more than once it means we're working on a
 		// circular hierarchy of process IDs. This can happen if a parent
@@ -77,7 +147,7 @@ func isRepeat(pid ID, seen []ID) (lowest ID, repeated bool) {
 	return lowest, false
 }
 
-func childNodes(root, parent ID, nodes map[ID]Process, hierarchy map[ID][]ID) []Node {
+func childNodes(root, parent ID, nodes map[ID]Process, hierarchy map[ID][]ID, pseudo map[ID]bool) []Node {
 	childIDs := hierarchy[parent]
 	if len(childIDs) == 0 {
 		return nil
@@ -89,7 +159,8 @@ func childNodes(root, parent ID, nodes map[ID]Process, hierarchy map[ID][]ID) []
 		}
 		children = append(children, Node{
 			Process:  nodes[child],
-			Children: childNodes(root, child, nodes, hierarchy),
+			Children: childNodes(root, child, nodes, hierarchy, pseudo),
+			Pseudo:   pseudo[child],
 		})
 	}
 	return children
@@ -28,3 +28,41 @@ func BenchmarkTreeWithCommands(b *testing.B) {
 		winproc.Tree(list)
 	}
 }
+
+func TestTreeAttachConsoleHosts(t *testing.T) {
+	procs := []winproc.Process{
+		{ID: 100, ParentID: 100, Name: "explorer.exe"},
+		{ID: 200, ParentID: 100, Name: "cmd.exe", ConsoleHostID: 300},
+		{ID: 300, ParentID: 100, Name: "conhost.exe"},
+		{ID: 400, ParentID: 200, Name: "ping.exe", ConsoleHostID: 300},
+	}
+
+	tree := winproc.Tree(procs, winproc.AttachConsoleHosts)
+	if len(tree) != 1 || tree[0].ID != 100 {
+		t.Fatalf("unexpected roots: %v", tree)
+	}
+
+	explorer := tree[0]
+	if len(explorer.Children) != 1 || explorer.Children[0].ID != 200 {
+		t.Fatalf("unexpected children of explorer.exe: %v", explorer.Children)
+	}
+
+	cmd := explorer.Children[0]
+	if len(cmd.Children) != 2 {
+		t.Fatalf("unexpected children of cmd.exe: %v", cmd.Children)
+	}
+	for _, child := range cmd.Children {
+		switch child.ID {
+		case 300:
+			if !child.Pseudo {
+				t.Errorf("conhost.exe was not marked as a pseudo-child")
+			}
+		case 400:
+			if child.Pseudo {
+				t.Errorf("ping.exe was marked as a pseudo-child")
+			}
+		default:
+			t.Errorf("unexpected child of cmd.exe: %v", child)
+		}
+	}
+}
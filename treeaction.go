@@ -3,5 +3,301 @@
 
 package winproc
 
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
 // TreeAction is a function that takes action on a tree.
 type TreeAction func(tree []Node) error
+
+// TreeOperation is a function that takes action on a tree and reports the
+// outcome for each process that it was applied to. The outcomes are
+// returned whether or not the operation succeeded.
+type TreeOperation func(tree []Node) ([]NodeOutcome, error)
+
+// Action returns a tree action that performs the operation and discards
+// its outcomes.
+func (op TreeOperation) Action() TreeAction {
+	return func(tree []Node) error {
+		_, err := op(tree)
+		return err
+	}
+}
+
+// maxTreeRescans is the maximum number of times that a tree action will
+// re-scan the system for children spawned while it was in progress.
+const maxTreeRescans = 8
+
+// NodeOutcome describes the result of a tree action for a single process.
+type NodeOutcome struct {
+	Process Process
+	Exited  bool  // The process exited before the action could be applied
+	Err     error // The error encountered, if the action failed
+}
+
+// TreeActionError is returned by a tree action that failed for one or more
+// processes. It holds the outcome for every process that the action was
+// applied to.
+type TreeActionError struct {
+	Outcomes []NodeOutcome
+}
+
+// Error returns a string representation of the error.
+func (e *TreeActionError) Error() string {
+	var (
+		failed int
+		first  error
+	)
+	for _, outcome := range e.Outcomes {
+		if outcome.Err != nil {
+			if first == nil {
+				first = fmt.Errorf("PID %d: %w", outcome.Process.ID, outcome.Err)
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("tree action failed for %d of %d processes: %v", failed, len(e.Outcomes), first)
+}
+
+// Unwrap returns the errors encountered for each process.
+func (e *TreeActionError) Unwrap() []error {
+	var errs []error
+	for _, outcome := range e.Outcomes {
+		if outcome.Err != nil {
+			errs = append(errs, outcome.Err)
+		}
+	}
+	return errs
+}
+
+// TerminateTree returns a tree operation that terminates each process in
+// the tree with the given exit code. Children are terminated before their
+// parents.
+//
+// Processes are subject to the default safety policy. Use
+// Policy.TerminateTree to override it.
+func TerminateTree(exitCode uint32) TreeOperation {
+	return Policy{}.TerminateTree(exitCode)
+}
+
+// WaitTree returns a tree operation that waits until each process in the
+// tree has exited or ctx is cancelled.
+func WaitTree(ctx context.Context) TreeOperation {
+	walker := treeWalker{
+		rescan: true,
+		apply: func(ref *Ref) error {
+			return ref.Wait(ctx)
+		},
+	}
+	return walker.run
+}
+
+// PrioritizeTree returns a tree operation that sets the priority class of
+// each process in the tree.
+func PrioritizeTree(class PriorityClass) TreeOperation {
+	walker := treeWalker{
+		rights: processaccess.SetInformation,
+		rescan: true,
+		apply: func(ref *Ref) error {
+			return ref.SetPriority(class)
+		},
+	}
+	return walker.run
+}
+
+// SuspendTree is a tree operation that suspends each process in the tree.
+// It suspends parents before their children, so that a parent can't spawn
+// new children while they are being suspended.
+//
+// Processes are subject to the default safety policy. Use
+// Policy.SuspendTree to override it.
+func SuspendTree(tree []Node) ([]NodeOutcome, error) {
+	return Policy{}.SuspendTree(tree)
+}
+
+// ResumeTree is a tree operation that resumes each process in the tree. It
+// resumes children before their parents, which is the reverse of
+// SuspendTree.
+//
+// Unlike the other tree operations it does not re-scan for new children,
+// because suspended processes can't spawn them.
+func ResumeTree(tree []Node) ([]NodeOutcome, error) {
+	walker := treeWalker{
+		rights:   processaccess.SuspendResume,
+		bottomUp: true,
+		apply:    (*Ref).Resume,
+	}
+	return walker.run(tree)
+}
+
+// treeWalker applies a function to each process in a tree.
+type treeWalker struct {
+	rights   processaccess.Rights // Access rights needed by apply
+	bottomUp bool                 // Apply to children before parents
+	rescan   bool                 // Look for children spawned along the way
+	guarded  bool                 // Apply is destructive
+	policy   Policy               // Safety policy for destructive operations
+	apply    func(ref *Ref) error
+}
+
+// terminateWalker returns a tree walker that terminates processes.
+func terminateWalker(exitCode uint32) treeWalker {
+	return treeWalker{
+		rights:   processaccess.Terminate,
+		bottomUp: true,
+		rescan:   true,
+		guarded:  true,
+		apply: func(ref *Ref) error {
+			return ref.Terminate(exitCode)
+		},
+	}
+}
+
+// suspendWalker returns a tree walker that suspends processes.
+func suspendWalker() treeWalker {
+	return treeWalker{
+		rights:  processaccess.SuspendResume,
+		rescan:  true,
+		guarded: true,
+		apply:   (*Ref).Suspend,
+	}
+}
+
+// run applies the walker to each process in tree. When rescanning is
+// enabled it then applies it to children that were spawned after it
+// started by processes that it was successfully applied to.
+func (w treeWalker) run(tree []Node) ([]NodeOutcome, error) {
+	var (
+		outcomes []NodeOutcome
+		start    = time.Now()
+		visited  = make(map[ID]time.Time) // Maps process IDs to creation times
+		parents  = make(map[ID]time.Time) // Visited processes that succeeded
+	)
+
+	w.walk(tree, visited, parents, &outcomes)
+	for i := 0; w.rescan && i < maxTreeRescans; i++ {
+		spawned, err := spawnedChildren(start, parents, visited)
+		if err != nil || len(spawned) == 0 {
+			break
+		}
+		w.walk(spawned, visited, parents, &outcomes)
+	}
+
+	for _, outcome := range outcomes {
+		if outcome.Err != nil {
+			return outcomes, &TreeActionError{Outcomes: outcomes}
+		}
+	}
+	return outcomes, nil
+}
+
+// walk applies the walker to each process in tree.
+func (w treeWalker) walk(tree []Node, visited, parents map[ID]time.Time, outcomes *[]NodeOutcome) {
+	for i := range tree {
+		if w.bottomUp {
+			w.walk(tree[i].Children, visited, parents, outcomes)
+		}
+
+		outcome := w.visit(tree[i].Process, visited)
+		if outcome.Err == nil {
+			parents[outcome.Process.ID] = visited[outcome.Process.ID]
+		}
+		*outcomes = append(*outcomes, outcome)
+
+		if !w.bottomUp {
+			w.walk(tree[i].Children, visited, parents, outcomes)
+		}
+	}
+}
+
+// visit applies the walker to proc.
+func (w treeWalker) visit(proc Process, visited map[ID]time.Time) NodeOutcome {
+	visited[proc.ID] = proc.Times.Creation
+
+	// Kernel processes can't be opened, but they should still be refused
+	// rather than reported as inaccessible
+	if w.guarded && kernelProcess(proc.ID) {
+		return NodeOutcome{Process: proc, Err: w.policy.Check(proc)}
+	}
+
+	ref, err := proc.Ref(w.rights, processaccess.QueryLimitedInformation, processaccess.Synchronize)
+	if err != nil {
+		if errorKind(err) == ErrProcessGone {
+			return NodeOutcome{Process: proc, Exited: true}
+		}
+		return NodeOutcome{Process: proc, Err: err}
+	}
+	defer ref.Close()
+	ref.SetPolicy(w.policy)
+
+	if times, err := ref.Times(); err == nil {
+		visited[proc.ID] = times.Creation
+	}
+
+	if err := w.apply(ref); err != nil {
+		if exited, _ := ref.exited(); exited {
+			return NodeOutcome{Process: proc, Exited: true}
+		}
+		return NodeOutcome{Process: proc, Err: err}
+	}
+
+	return NodeOutcome{Process: proc}
+}
+
+// spawnedChildren returns a tree of the processes that were created after
+// start and are descendants of the given parents, but haven't been
+// visited. Processes that existed before start are ignored, because they
+// were either part of the tree or deliberately left out of it.
+func spawnedChildren(start time.Time, parents, visited map[ID]time.Time) ([]Node, error) {
+	procs, err := List(CollectTimes)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[ID]time.Time, len(parents))
+	for id, creation := range parents {
+		known[id] = creation
+	}
+
+	// Add descendants until no more are found
+	var spawned []Process
+	for added := true; added; {
+		added = false
+		for _, proc := range procs {
+			if _, ok := visited[proc.ID]; ok {
+				continue
+			}
+			if proc.Times.Creation.IsZero() || proc.Times.Creation.Before(start) {
+				continue
+			}
+			if _, ok := known[proc.ID]; ok || !isChild(proc, known) {
+				continue
+			}
+			known[proc.ID] = proc.Times.Creation
+			spawned = append(spawned, proc)
+			added = true
+		}
+	}
+
+	return Tree(spawned), nil
+}
+
+// isChild returns true if proc is a child of a known process. Processes
+// created before their parent are ignored, because they are the children
+// of an earlier process with the same ID. So are processes whose parent or
+// own creation time is unknown, because their relationship can't be
+// verified.
+func isChild(proc Process, known map[ID]time.Time) bool {
+	if proc.ParentID == proc.ID {
+		return false
+	}
+	creation, ok := known[proc.ParentID]
+	if !ok || creation.IsZero() || proc.Times.Creation.IsZero() {
+		return false
+	}
+	return !proc.Times.Creation.Before(creation)
+}
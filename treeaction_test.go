@@ -0,0 +1,228 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+// singleTree returns a tree holding only the process with the given ID.
+func singleTree(t *testing.T, pid int) []winproc.Node {
+	t.Helper()
+
+	procs, err := winproc.List(
+		winproc.Include(winproc.MatchID(winproc.ID(pid))),
+		winproc.CollectTimes,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := winproc.Tree(procs)
+	if len(tree) != 1 {
+		t.Fatalf("expected 1 root, found %d", len(tree))
+	}
+	return tree
+}
+
+// findChild waits for a process with the given name to be spawned by the
+// process with the given ID.
+func findChild(t *testing.T, parent int, name string) winproc.Process {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		procs, err := winproc.List(winproc.CollectTimes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, proc := range procs {
+			if proc.ParentID == winproc.ID(parent) && strings.EqualFold(proc.Name, name) {
+				return proc
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("%s was not spawned by PID %d", name, parent)
+	return winproc.Process{}
+}
+
+// killProcess terminates the process with the given ID.
+func killProcess(pid winproc.ID) {
+	if ref, err := winproc.Open(pid, processaccess.Terminate); err == nil {
+		ref.Terminate(1)
+		ref.Close()
+	}
+}
+
+func TestTerminateTree(t *testing.T) {
+	command := exec.Command("notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	tree := singleTree(t, command.Process.Pid)
+
+	outcomes, err := winproc.TerminateTree(1)(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, found %d", len(outcomes))
+	}
+	if outcomes[0].Exited {
+		t.Fatal("the process was reported as having exited on its own")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := winproc.WaitTree(ctx)(tree); err != nil {
+		t.Fatal(err)
+	}
+
+	// The process has exited, so terminating it again should say so
+	outcomes, err = winproc.TerminateTree(1)(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != 1 || !outcomes[0].Exited {
+		t.Fatalf("expected the process to be reported as exited: %+v", outcomes)
+	}
+}
+
+func TestPrioritizeTree(t *testing.T) {
+	command := exec.Command("notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	tree := singleTree(t, command.Process.Pid)
+
+	if _, err := winproc.PrioritizeTree(winproc.PriorityBelowNormal)(tree); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := winproc.Open(winproc.ID(command.Process.Pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if class, err := ref.Priority(); err != nil {
+		t.Fatal(err)
+	} else if class != winproc.PriorityBelowNormal {
+		t.Fatalf("expected priority %s, got %s", winproc.PriorityBelowNormal, class)
+	}
+}
+
+func TestSuspendResumeTree(t *testing.T) {
+	command := exec.Command("notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	tree := singleTree(t, command.Process.Pid)
+
+	ref, err := winproc.Open(winproc.ID(command.Process.Pid), processaccess.QueryLimitedInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Close()
+
+	if _, err := winproc.SuspendTree(tree); err != nil {
+		t.Fatal(err)
+	}
+	if suspended, err := ref.Suspended(); err != nil {
+		t.Fatal(err)
+	} else if !suspended {
+		t.Fatal("the process was not suspended")
+	}
+
+	if _, err := winproc.ResumeTree(tree); err != nil {
+		t.Fatal(err)
+	}
+	if suspended, err := ref.Suspended(); err != nil {
+		t.Fatal(err)
+	} else if suspended {
+		t.Fatal("the process was not resumed")
+	}
+}
+
+func TestTreeRescanSpawned(t *testing.T) {
+	// The shell spawns notepad after a short delay and then exits
+	command := exec.Command("cmd", "/c", "ping -n 2 127.0.0.1 >nul & start notepad")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	tree := singleTree(t, command.Process.Pid)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	type result struct {
+		outcomes []winproc.NodeOutcome
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		outcomes, err := winproc.WaitTree(ctx)(tree)
+		done <- result{outcomes, err}
+	}()
+
+	child := findChild(t, command.Process.Pid, "notepad.exe")
+	killProcess(child.ID)
+
+	r := <-done
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	for _, outcome := range r.outcomes {
+		if outcome.Process.ID == child.ID {
+			return
+		}
+	}
+	t.Fatalf("the spawned child (PID %d) was not waited on", child.ID)
+}
+
+func TestTreeRescanBoundary(t *testing.T) {
+	// The shell waits for ping, which exists before the action starts
+	command := exec.Command("cmd", "/c", "ping -n 30 127.0.0.1 >nul")
+	if err := command.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer command.Process.Kill()
+
+	child := findChild(t, command.Process.Pid, "ping.exe")
+	defer killProcess(child.ID)
+
+	tree := singleTree(t, command.Process.Pid)
+
+	outcomes, err := winproc.TerminateTree(1)(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, found %d", len(outcomes))
+	}
+
+	ref, err := child.Ref(processaccess.Synchronize)
+	if err != nil {
+		t.Fatalf("the excluded child was terminated: %v", err)
+	}
+	defer ref.Close()
+	if status, err := ref.ExitStatus(); err != winproc.ErrProcessStillActive {
+		t.Fatalf("the excluded child was terminated: %v %v", status, err)
+	}
+}
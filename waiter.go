@@ -0,0 +1,270 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+
+	"github.com/gentlemanautomaton/winproc/threadpoolapi"
+)
+
+// ErrWaiterClosed is returned when a process is added to a waiter that has
+// been closed.
+var ErrWaiterClosed = errors.New("the waiter has been closed")
+
+// Waiter waits for many processes to exit at the same time.
+//
+// Unlike Ref.Wait, which blocks an operating system thread for each call, a
+// Waiter relies on the system thread pool. Each pool thread waits on up to
+// 63 processes, so hundreds of processes can be waited on by a handful of
+// threads.
+//
+// When a process exits its reference is delivered on the channel returned
+// by Exited. Each reference is delivered once.
+//
+// It is safe for concurrent use.
+type Waiter struct {
+	mutex   sync.Mutex
+	entries map[*waitEntry]struct{}
+	ready   []*waitEntry  // Entries that have been signaled
+	notify  chan struct{} // Wakes the dispatcher when ready isn't empty
+	done    chan struct{} // Closed when the waiter is closed
+	stopped chan struct{} // Closed when the dispatcher has exited
+	exits   chan *Ref
+	closed  bool
+}
+
+// waitEntry is a registered wait for a single process.
+type waitEntry struct {
+	ref    *Ref
+	handle syscall.Handle // Duplicate of the process handle
+	wait   syscall.Handle // Thread pool wait handle
+}
+
+// NewWaiter returns a new waiter. It must be closed when it is no longer
+// needed.
+func NewWaiter() *Waiter {
+	w := &Waiter{
+		entries: make(map[*waitEntry]struct{}),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		exits:   make(chan *Ref),
+	}
+	go w.dispatch()
+	return w
+}
+
+// Add causes the waiter to wait for the process referenced by ref to exit.
+// If the process has already exited it will be delivered right away.
+//
+// The reference must have been opened with the Synchronize access right.
+// It may be closed after Add returns, but the same reference will still be
+// delivered when the process exits.
+func (w *Waiter) Add(ref *Ref) error {
+	handle, err := ref.DuplicateHandle()
+	if err != nil {
+		return err
+	}
+
+	entry := &waitEntry{ref: ref, handle: handle}
+
+	// Hold the lock while registering, so that the callback can't observe
+	// the entry before its wait handle has been recorded
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		syscall.CloseHandle(handle)
+		return ErrWaiterClosed
+	}
+
+	entry.wait, err = threadpoolapi.RegisterWait(handle, threadpoolapi.Infinite, threadpoolapi.ExecuteOnlyOnce, func(timedOut bool) {
+		w.signal(entry)
+	})
+	if err != nil {
+		syscall.CloseHandle(handle)
+		return err
+	}
+
+	w.entries[entry] = struct{}{}
+	return nil
+}
+
+// Remove stops waiting for ref. It returns false if the waiter wasn't
+// waiting for ref, or if its exit has already been observed.
+func (w *Waiter) Remove(ref *Ref) bool {
+	w.mutex.Lock()
+	var removed []*waitEntry
+	for entry := range w.entries {
+		if entry.ref == ref {
+			delete(w.entries, entry)
+			removed = append(removed, entry)
+		}
+	}
+	w.mutex.Unlock()
+
+	for _, entry := range removed {
+		entry.release()
+	}
+	return len(removed) > 0
+}
+
+// Len returns the number of processes that the waiter is waiting for.
+func (w *Waiter) Len() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return len(w.entries)
+}
+
+// Exited returns a channel on which references are delivered as their
+// processes exit. The channel is closed when the waiter is closed.
+func (w *Waiter) Exited() <-chan *Ref {
+	return w.exits
+}
+
+// Close stops waiting for all processes and closes the channel returned by
+// Exited. Exits that haven't been received are discarded.
+func (w *Waiter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return ErrWaiterClosed
+	}
+	w.closed = true
+	entries := w.entries
+	ready := w.ready
+	w.entries = nil
+	w.ready = nil
+	w.mutex.Unlock()
+
+	close(w.done)
+	<-w.stopped
+
+	for entry := range entries {
+		entry.release()
+	}
+	for _, entry := range ready {
+		entry.release()
+	}
+
+	close(w.exits)
+	return nil
+}
+
+// signal is called by a thread pool thread when entry is signaled. It must
+// not block.
+func (w *Waiter) signal(entry *waitEntry) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, ok := w.entries[entry]; !ok {
+		// The entry has been removed
+		return
+	}
+	delete(w.entries, entry)
+	w.ready = append(w.ready, entry)
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch delivers signaled entries to the exits channel until the waiter
+// is closed.
+func (w *Waiter) dispatch() {
+	defer close(w.stopped)
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.notify:
+		}
+
+		for {
+			w.mutex.Lock()
+			if len(w.ready) == 0 {
+				w.mutex.Unlock()
+				break
+			}
+			entry := w.ready[0]
+			w.ready = w.ready[1:]
+			w.mutex.Unlock()
+
+			entry.release()
+
+			select {
+			case w.exits <- entry.ref:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// release unregisters the wait and closes the duplicated process handle.
+// It blocks until any callback in progress has returned.
+func (entry *waitEntry) release() {
+	threadpoolapi.UnregisterWait(entry.wait, true)
+	syscall.CloseHandle(entry.handle)
+}
+
+// WaitAny waits until any of the referenced processes has exited, or ctx
+// is cancelled. It returns the reference of the first process to exit.
+//
+// The references must have been opened with the Synchronize access right.
+func WaitAny(ctx context.Context, refs ...*Ref) (*Ref, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	w := NewWaiter()
+	defer w.Close()
+
+	for _, ref := range refs {
+		if err := w.Add(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	select {
+	case ref := <-w.Exited():
+		return ref, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WaitAll waits until all of the referenced processes have exited, or ctx
+// is cancelled.
+//
+// The references must have been opened with the Synchronize access right.
+func WaitAll(ctx context.Context, refs ...*Ref) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	w := NewWaiter()
+	defer w.Close()
+
+	for _, ref := range refs {
+		if err := w.Add(ref); err != nil {
+			return err
+		}
+	}
+
+	for remaining := len(refs); remaining > 0; remaining-- {
+		select {
+		case <-w.Exited():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/processaccess"
+)
+
+// startWaitable starts n instances of notepad and returns references to
+// them that can be waited on.
+func startWaitable(t *testing.T, n int) ([]*exec.Cmd, []*winproc.Ref) {
+	t.Helper()
+
+	var (
+		commands []*exec.Cmd
+		refs     []*winproc.Ref
+	)
+	for i := 0; i < n; i++ {
+		command := exec.Command("notepad")
+		if err := command.Start(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { command.Process.Kill() })
+
+		ref, err := winproc.FromCmd(command, processaccess.Synchronize, processaccess.QueryLimitedInformation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { ref.Close() })
+
+		commands = append(commands, command)
+		refs = append(refs, ref)
+	}
+	return commands, refs
+}
+
+func TestWaiter(t *testing.T) {
+	// Exceed the 64 handle limit of WaitForMultipleObjects
+	commands, refs := startWaitable(t, 70)
+
+	w := winproc.NewWaiter()
+	defer w.Close()
+
+	for _, ref := range refs {
+		if err := w.Add(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := w.Len(); n != len(refs) {
+		t.Fatalf("waiting for %d processes, expected %d", n, len(refs))
+	}
+
+	for _, command := range commands {
+		command.Process.Kill()
+	}
+
+	timeout := time.After(10 * time.Second)
+	seen := make(map[*winproc.Ref]bool)
+	for len(seen) < len(refs) {
+		select {
+		case ref := <-w.Exited():
+			if seen[ref] {
+				t.Fatal("reference delivered more than once")
+			}
+			seen[ref] = true
+		case <-timeout:
+			t.Fatalf("only %d of %d exits were delivered", len(seen), len(refs))
+		}
+	}
+}
+
+func TestWaiterRemove(t *testing.T) {
+	commands, refs := startWaitable(t, 1)
+
+	w := winproc.NewWaiter()
+	defer w.Close()
+
+	if err := w.Add(refs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Remove(refs[0]) {
+		t.Fatal("the reference was not removed")
+	}
+	commands[0].Process.Kill()
+
+	select {
+	case <-w.Exited():
+		t.Fatal("exit delivered for a removed reference")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWaitAny(t *testing.T) {
+	commands, refs := startWaitable(t, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := winproc.WaitAny(ctx, refs...); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	commands[1].Process.Kill()
+
+	ref, err := winproc.WaitAny(context.Background(), refs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref != refs[1] {
+		t.Fatal("the wrong reference was returned")
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	commands, refs := startWaitable(t, 3)
+
+	commands[0].Process.Kill()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := winproc.WaitAll(ctx, refs...); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	for _, command := range commands[1:] {
+		command.Process.Kill()
+	}
+
+	if err := winproc.WaitAll(context.Background(), refs...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -12,6 +12,10 @@ import (
 // or the context is cancelled. It sends differences in the process list on
 // the returned channel.
 //
+// Information collected by collector options that doesn't change after
+// process creation is cached for the lifetime of the watch, so that only
+// volatile information is collected on each poll.
+//
 // This function is experimental and may be changed in future revisions.
 func Watch(ctx context.Context, interval time.Duration, chanSize int, options ...CollectionOption) <-chan ChangeSet {
 	ch := make(chan ChangeSet, chanSize)
@@ -23,6 +27,7 @@ func Watch(ctx context.Context, interval time.Duration, chanSize int, options ..
 		defer ticker.Stop()
 
 		known := make(map[UniqueID]Process)
+		options := NewCache().wrap(options)
 
 		for {
 			select {
@@ -30,7 +35,11 @@ func Watch(ctx context.Context, interval time.Duration, chanSize int, options ..
 				ch <- ChangeSet{Err: ctx.Err(), Time: time.Now()}
 				return
 			case <-ticker.C:
-				list, err := List(options...)
+				list, err := ListContext(ctx, options...)
+				if err == nil {
+					// Don't report changes based on a partial list
+					err = ctx.Err()
+				}
 				if err != nil {
 					ch <- ChangeSet{Err: err, Time: time.Now()}
 					return
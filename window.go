@@ -0,0 +1,109 @@
+//go:build windows
+// +build windows
+
+package winproc
+
+import "github.com/gentlemanautomaton/winproc/winuser"
+
+// Window holds information about a top-level window owned by a process.
+type Window struct {
+	Handle  uintptr
+	Title   string
+	Class   string
+	Visible bool
+}
+
+// String returns a string representation of the window.
+func (w Window) String() string {
+	if w.Title == "" {
+		return w.Class
+	}
+	return w.Title
+}
+
+// WindowEntry is an entry in a table of windows. It identifies the process
+// and thread that created a window.
+type WindowEntry struct {
+	Window
+	ProcessID ID
+	ThreadID  uint32
+}
+
+// A WindowSource provides a table of top-level windows.
+type WindowSource interface {
+	Windows() ([]WindowEntry, error)
+}
+
+// WindowCollector is a collection option that attaches top-level windows
+// to the processes that own them. If the table of windows can't be
+// retrieved, the failure is recorded in the Errors field of each process
+// and attributed to CollectWindows.
+type WindowCollector struct {
+	// Source provides the table of windows. If it is nil the windows of
+	// the calling thread's desktop are enumerated.
+	Source WindowSource
+}
+
+// Apply applies the window collector to the collection.
+func (c WindowCollector) Apply(col *Collection) {
+	source := c.Source
+	if source == nil {
+		source = Desktop{}
+	}
+
+	entries, err := source.Windows()
+	if err != nil {
+		for i := range col.Procs {
+			if !col.Excluded[i] {
+				col.Procs[i].fail(CollectWindows, "windows", err)
+			}
+		}
+		return
+	}
+
+	owned := make(map[ID][]Window)
+	for _, entry := range entries {
+		owned[entry.ProcessID] = append(owned[entry.ProcessID], entry.Window)
+	}
+
+	for i := range col.Procs {
+		if col.Excluded[i] {
+			continue
+		}
+		if windows := owned[col.Procs[i].ID]; len(windows) > 0 {
+			col.Procs[i].Windows = windows
+		}
+	}
+}
+
+// Desktop is a WindowSource that enumerates the top-level windows of the
+// calling thread's desktop.
+type Desktop struct{}
+
+// Windows returns a table of the top-level windows on the desktop in
+// z-order.
+func (Desktop) Windows() (entries []WindowEntry, err error) {
+	err = winuser.EnumWindows(func(hwnd winuser.HWND) bool {
+		threadID, processID, err := winuser.WindowThreadProcessID(hwnd)
+		if err != nil {
+			return true // The window was probably destroyed
+		}
+		title, _ := winuser.WindowText(hwnd)
+		class, _ := winuser.ClassName(hwnd)
+		entries = append(entries, WindowEntry{
+			Window: Window{
+				Handle:  uintptr(hwnd),
+				Title:   title,
+				Class:   class,
+				Visible: winuser.IsWindowVisible(hwnd),
+			},
+			ProcessID: ID(processID),
+			ThreadID:  threadID,
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
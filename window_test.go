@@ -0,0 +1,89 @@
+//go:build windows
+// +build windows
+
+package winproc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gentlemanautomaton/winproc"
+	"github.com/gentlemanautomaton/winproc/winuser"
+)
+
+type fakeWindowTable []winproc.WindowEntry
+
+func (table fakeWindowTable) Windows() ([]winproc.WindowEntry, error) {
+	return table, nil
+}
+
+func TestWindowCollector(t *testing.T) {
+	table := fakeWindowTable{
+		{Window: winproc.Window{Handle: 0x10, Title: "Untitled - Notepad", Class: "Notepad", Visible: true}, ProcessID: 20},
+		{Window: winproc.Window{Handle: 0x11, Class: "IME"}, ProcessID: 20},
+		{Window: winproc.Window{Handle: 0x20, Class: "MSCTFIME UI"}, ProcessID: 30},
+		{Window: winproc.Window{Handle: 0x30, Title: "Settings", Visible: true}, ProcessID: 40},
+	}
+
+	col := winproc.Collection{
+		Procs: []winproc.Process{
+			{ID: 10, Name: "svchost.exe"},
+			{ID: 20, Name: "notepad.exe"},
+			{ID: 30, Name: "ctfmon.exe"},
+			{ID: 40, Name: "SystemSettings.exe"},
+		},
+		Excluded: []bool{false, false, false, true},
+	}
+
+	winproc.WindowCollector{Source: table}.Apply(&col)
+
+	if windows := col.Procs[1].Windows; len(windows) != 2 || windows[0].Handle != 0x10 || windows[1].Handle != 0x11 {
+		t.Errorf("notepad.exe: unexpected windows: %v", windows)
+	}
+	if windows := col.Procs[3].Windows; len(windows) != 0 {
+		t.Errorf("SystemSettings.exe: windows were collected for an excluded process: %v", windows)
+	}
+
+	visible := winproc.HasVisibleWindow()
+	for i, want := range []bool{false, true, false, false} {
+		if got := visible(col.Procs[i]); got != want {
+			t.Errorf("%s: HasVisibleWindow returned %t, expected %t", col.Procs[i].Name, got, want)
+		}
+	}
+}
+
+type failingWindowTable struct{}
+
+func (failingWindowTable) Windows() ([]winproc.WindowEntry, error) {
+	return nil, errors.New("no desktop")
+}
+
+func TestWindowCollectorError(t *testing.T) {
+	col := winproc.Collection{
+		Procs:    []winproc.Process{{ID: 20, Name: "notepad.exe"}},
+		Excluded: []bool{false},
+	}
+
+	winproc.WindowCollector{Source: failingWindowTable{}}.Apply(&col)
+
+	if windows := col.Procs[0].Windows; len(windows) != 0 {
+		t.Errorf("unexpected windows: %v", windows)
+	}
+	if err := col.Procs[0].Err(winproc.CollectWindows); err == nil {
+		t.Errorf("the failure was not recorded")
+	}
+}
+
+func TestEnumWindowsStop(t *testing.T) {
+	calls := 0
+	err := winuser.EnumWindows(func(winuser.HWND) bool {
+		calls++
+		return false
+	})
+	if err != nil {
+		t.Fatalf("stopping enumeration early returned an error: %v", err)
+	}
+	if calls > 1 {
+		t.Fatalf("enumeration continued after being stopped (%d calls)", calls)
+	}
+}
@@ -0,0 +1,221 @@
+//go:build windows
+// +build windows
+
+package winuser
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	moduser32 = windows.NewLazySystemDLL("user32.dll")
+
+	procEnumWindows              = moduser32.NewProc("EnumWindows")
+	procGetClassName             = moduser32.NewProc("GetClassNameW")
+	procGetWindowText            = moduser32.NewProc("GetWindowTextW")
+	procGetWindowTextLength      = moduser32.NewProc("GetWindowTextLengthW")
+	procGetWindowThreadProcessID = moduser32.NewProc("GetWindowThreadProcessId")
+	procIsWindowVisible          = moduser32.NewProc("IsWindowVisible")
+	procPostMessage              = moduser32.NewProc("PostMessageW")
+)
+
+// Window messages.
+const (
+	MessageClose = 0x0010 // WM_CLOSE
+)
+
+// HWND is a handle to a window.
+type HWND uintptr
+
+// EnumWindows calls fn for each top-level window on the screen. Enumeration
+// stops when fn returns false. It calls the EnumWindows windows API
+// function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-enumwindows
+func EnumWindows(fn func(hwnd HWND) bool) (err error) {
+	stopped := false
+	id := enumerators.add(func(hwnd HWND) bool {
+		if fn(hwnd) {
+			return true
+		}
+		stopped = true
+		return false
+	})
+	defer enumerators.remove(id)
+
+	r0, _, e := syscall.Syscall(
+		procEnumWindows.Addr(),
+		2,
+		enumWindowsCallback,
+		id,
+		0)
+	if r0 == 0 && !stopped {
+		// EnumWindows also returns zero when fn stops the enumeration, in
+		// which case the last error may be stale and must be ignored
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// WindowThreadProcessID returns the IDs of the thread and process that
+// created a window. It calls the GetWindowThreadProcessId windows API
+// function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-getwindowthreadprocessid
+func WindowThreadProcessID(hwnd HWND) (threadID, processID uint32, err error) {
+	r0, _, e := syscall.Syscall(
+		procGetWindowThreadProcessID.Addr(),
+		2,
+		uintptr(hwnd),
+		uintptr(unsafe.Pointer(&processID)),
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return uint32(r0), processID, err
+}
+
+// ClassName returns the name of the class to which a window belongs. It
+// calls the GetClassNameW windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-getclassnamew
+func ClassName(hwnd HWND) (name string, err error) {
+	// Class names are limited to 256 characters
+	var buffer [257]uint16
+
+	r0, _, e := syscall.Syscall(
+		procGetClassName.Addr(),
+		3,
+		uintptr(hwnd),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(len(buffer)))
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+		return "", err
+	}
+	return syscall.UTF16ToString(buffer[:r0]), nil
+}
+
+// WindowText returns the text of a window's title bar. It calls the
+// GetWindowTextLengthW and GetWindowTextW windows API functions.
+//
+// WindowText returns an empty string if the window has no title.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-getwindowtextw
+func WindowText(hwnd HWND) (text string, err error) {
+	length, _, _ := syscall.Syscall(
+		procGetWindowTextLength.Addr(),
+		1,
+		uintptr(hwnd),
+		0,
+		0)
+	if length == 0 {
+		return "", nil
+	}
+
+	buffer := make([]uint16, length+1)
+	r0, _, e := syscall.Syscall(
+		procGetWindowText.Addr(),
+		3,
+		uintptr(hwnd),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(len(buffer)))
+	if r0 == 0 && e != 0 {
+		return "", syscall.Errno(e)
+	}
+	return syscall.UTF16ToString(buffer[:r0]), nil
+}
+
+// IsWindowVisible returns true if a window has the WS_VISIBLE style. It
+// calls the IsWindowVisible windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-iswindowvisible
+func IsWindowVisible(hwnd HWND) bool {
+	r0, _, _ := syscall.Syscall(
+		procIsWindowVisible.Addr(),
+		1,
+		uintptr(hwnd),
+		0,
+		0)
+	return r0 != 0
+}
+
+// PostMessage places a message in the message queue of the thread that
+// created a window and returns without waiting for it to be processed. It
+// calls the PostMessageW windows API function.
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-postmessagew
+func PostMessage(hwnd HWND, msg uint32, wparam, lparam uintptr) (err error) {
+	r0, _, e := syscall.Syscall6(
+		procPostMessage.Addr(),
+		4,
+		uintptr(hwnd),
+		uintptr(msg),
+		wparam,
+		lparam,
+		0,
+		0)
+	if r0 == 0 {
+		if e != 0 {
+			err = syscall.Errno(e)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// enumWindowsCallback is shared by all calls to EnumWindows, because the
+// number of callbacks that can be created is limited. The application
+// defined value passed to it identifies the enumerator to be called.
+var enumWindowsCallback = syscall.NewCallback(func(hwnd HWND, id uintptr) uintptr {
+	if fn := enumerators.get(id); fn != nil && fn(hwnd) {
+		return 1
+	}
+	return 0
+})
+
+var enumerators = enumeratorSet{fns: make(map[uintptr]func(HWND) bool)}
+
+// enumeratorSet keeps track of the enumeration functions that are in use.
+type enumeratorSet struct {
+	mutex sync.RWMutex
+	next  uintptr
+	fns   map[uintptr]func(HWND) bool
+}
+
+func (set *enumeratorSet) add(fn func(HWND) bool) uintptr {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	set.next++
+	set.fns[set.next] = fn
+	return set.next
+}
+
+func (set *enumeratorSet) get(id uintptr) func(HWND) bool {
+	set.mutex.RLock()
+	defer set.mutex.RUnlock()
+	return set.fns[id]
+}
+
+func (set *enumeratorSet) remove(id uintptr) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	delete(set.fns, id)
+}